@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 
@@ -22,10 +24,14 @@ const (
 	flagURL     = "url"
 	flagToken   = "token"
 	flagVerbose = "verbose"
+	flagDryRun  = "dry-run"
+
+	configProfiles = "profiles"
 
 	defaultURL     = "https://packagecloud.io"
 	defaultToken   = ""
 	defaultVerbose = false
+	defaultDryRun  = false
 )
 
 var (
@@ -111,12 +117,14 @@ func newRootCmd() (*cobra.Command, error) {
 	cmd.PersistentFlags().String(flagURL, defaultURL, "website url to use")
 	cmd.PersistentFlags().String(flagToken, defaultToken, "token to use for authentication")
 	cmd.PersistentFlags().Bool(flagVerbose, defaultVerbose, "enable verbose mode")
+	cmd.PersistentFlags().Bool(flagDryRun, defaultDryRun, "show what would be changed without modifying any repository")
 
 	cmd.MarkFlagRequired(flagToken)
 
 	viper.BindPFlag(flagURL, cmd.PersistentFlags().Lookup(flagURL))
 	viper.BindPFlag(flagToken, cmd.PersistentFlags().Lookup(flagToken))
 	viper.BindPFlag(flagVerbose, cmd.PersistentFlags().Lookup(flagVerbose))
+	viper.BindPFlag(flagDryRun, cmd.PersistentFlags().Lookup(flagDryRun))
 	viper.BindEnv(flagToken, envToken)
 
 	getClientFn := func() (*packagecloud.Client, error) {
@@ -132,7 +140,31 @@ func newRootCmd() (*cobra.Command, error) {
 		return packagecloud.NewClient(config), nil
 	}
 
-	command.AddCommands(cmd, getClientFn)
+	getProfileClientFn := func(profile string) (*packagecloud.Client, error) {
+		if profile == "" {
+			return getClientFn()
+		}
+
+		sub := viper.Sub(fmt.Sprintf("%s.%s", configProfiles, profile))
+		if sub == nil {
+			return nil, fmt.Errorf("profile not found in config file: %s", profile)
+		}
+
+		config := packagecloud.Config{
+			ServiceURL: defaultURL,
+		}
+		if err := sub.Unmarshal(&config); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal config for profile %s: %s", profile, err)
+		}
+		config.DryRun = config.DryRun || viper.GetBool(flagDryRun)
+		if err := config.Validate(); err != nil {
+			return nil, fmt.Errorf("config validation failed for profile %s: %s", profile, err)
+		}
+
+		return packagecloud.NewClient(config), nil
+	}
+
+	command.AddCommands(cmd, getClientFn, getProfileClientFn)
 
 	return cmd, nil
 }
@@ -144,7 +176,10 @@ func main() {
 	}
 	cobra.OnInitialize(initCobra(rootCmd))
 
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		if argsErr, ok := err.(*commanderrors.ErrInvalidArgs); ok {
 			fmt.Printf("Error: %s\n\n", argsErr)
 			rootCmd.Help()
@@ -1,21 +1,37 @@
 package command
 
 import (
+	"github.com/amdprophet/packagecloud-go/command/copycmd"
+	"github.com/amdprophet/packagecloud-go/command/diff"
 	"github.com/amdprophet/packagecloud-go/command/distro"
+	"github.com/amdprophet/packagecloud-go/command/pipeline"
 	"github.com/amdprophet/packagecloud-go/command/promote"
+	"github.com/amdprophet/packagecloud-go/command/prune"
 	"github.com/amdprophet/packagecloud-go/command/push"
+	"github.com/amdprophet/packagecloud-go/command/retarget"
 	"github.com/amdprophet/packagecloud-go/command/search"
+	"github.com/amdprophet/packagecloud-go/command/snapshot"
+	"github.com/amdprophet/packagecloud-go/command/synccmd"
 	"github.com/amdprophet/packagecloud-go/command/versions"
+	"github.com/amdprophet/packagecloud-go/command/watch"
 	"github.com/amdprophet/packagecloud-go/packagecloud"
 	"github.com/spf13/cobra"
 )
 
-func AddCommands(rootCmd *cobra.Command, getClientFn packagecloud.GetClientFn) {
+func AddCommands(rootCmd *cobra.Command, getClientFn packagecloud.GetClientFn, getProfileClientFn packagecloud.GetProfileClientFn) {
 	rootCmd.AddCommand(
+		copycmd.CopyCommand(getProfileClientFn),
+		diff.DiffCommand(getClientFn),
 		distro.HelpCommand(getClientFn),
+		pipeline.HelpCommand(getClientFn),
 		push.PushCommand(getClientFn),
 		promote.HelpCommand(getClientFn),
+		prune.PruneCommand(getClientFn),
+		retarget.RetargetCommand(getClientFn),
 		search.SearchCommand(getClientFn),
+		snapshot.HelpCommand(getClientFn),
+		synccmd.SyncCommand(getClientFn),
 		versions.HelpCommand(getClientFn),
+		watch.WatchCommand(getClientFn),
 	)
 }
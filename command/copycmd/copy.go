@@ -0,0 +1,282 @@
+package copycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "text"
+
+	flagQuery      = "query"
+	shortFlagQuery = "q"
+
+	flagFilter      = "filter"
+	shortFlagFilter = "i"
+
+	flagDist      = "dist"
+	shortFlagDist = "d"
+
+	flagArch      = "arch"
+	shortFlagArch = "a"
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+
+	flagContinueOnError = "continue-on-error"
+
+	defaultContinueOnError = false
+)
+
+// parseProfileRepo parses a repository in the format [profile:]user/repo.
+func parseProfileRepo(s string) (string, packagecloud.Repo, error) {
+	var profile string
+	if i := strings.Index(s, ":"); i != -1 {
+		profile = s[:i]
+		s = s[i+1:]
+	}
+
+	repo, err := packagecloud.NewRepoFromString(s)
+	if err != nil {
+		return "", packagecloud.Repo{}, err
+	}
+
+	return profile, repo, nil
+}
+
+func CopyCommand(getProfileClientFn packagecloud.GetProfileClientFn) *cobra.Command {
+	var srcProfile, dstProfile string
+	var srcRepo, dstRepo packagecloud.Repo
+	var searchQuery *packagecloud.Query
+
+	name := "copy"
+	usage := fmt.Sprintf("%s <%s> <%s> (%s)",
+		name,
+		"[source profile:]source repository",
+		"[destination profile:]destination repository",
+		"-q | -i | -d | -a | query",
+	)
+	example := fmt.Sprintf("%s %s %s %s",
+		name,
+		"ecorp/production",
+		"selfhosted:ecorp/production",
+		"-q '1.4.3-3258'",
+	)
+
+	cmd := &cobra.Command{
+		Use:     usage,
+		Short:   "Copy packages between repositories on different accounts or packagecloud instances",
+		Example: example,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) < 2 || len(args) > 3 {
+				return newErrWithUsage("requires 2 or 3 arguments")
+			}
+
+			if profile, repo, err := parseProfileRepo(args[0]); err != nil {
+				msg := fmt.Sprintf("invalid source repo: %s", err)
+				return newErrWithUsage(msg)
+			} else {
+				srcProfile = profile
+				srcRepo = repo
+			}
+
+			if profile, repo, err := parseProfileRepo(args[1]); err != nil {
+				msg := fmt.Sprintf("invalid destination repo: %s", err)
+				return newErrWithUsage(msg)
+			} else {
+				dstProfile = profile
+				dstRepo = repo
+			}
+
+			if len(args) == 3 {
+				q, err := packagecloud.ParseQuery(args[2], time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			query, err := cmd.Flags().GetString(flagQuery)
+			if err != nil {
+				return err
+			}
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			options := packagecloud.SearchOptions{
+				RepoUser: srcRepo.User,
+				RepoName: srcRepo.Name,
+				Query:    query,
+				Filter:   filter,
+				Dist:     dist,
+				Arch:     arch,
+			}
+			if searchQuery != nil {
+				searchQuery.Apply(&options)
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			srcClient, err := getProfileClientFn(srcProfile)
+			if err != nil {
+				return err
+			}
+
+			dstClient, err := getProfileClientFn(dstProfile)
+			if err != nil {
+				return err
+			}
+
+			copyOptions := packagecloud.CopyOptions{
+				Concurrency:     concurrency,
+				ContinueOnError: continueOnError,
+			}
+			if format != "json" {
+				mu := &sync.Mutex{}
+				copyOptions.OnResult = func(result packagecloud.CopyResult) {
+					mu.Lock()
+					defer mu.Unlock()
+					printResult(result)
+				}
+			}
+
+			results, err := packagecloud.Copy(srcClient, options, dstClient, dstRepo, copyOptions)
+			if len(results) > 0 {
+				if err := printResults(format, dstClient.DryRun(), results); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to copy packages: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
+	cmd.Flags().StringP(flagQuery, shortFlagQuery, "", "search string to search for package filename(s)")
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to copy at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep copying the remaining packages when a copy fails")
+
+	return cmd
+}
+
+func printResult(result packagecloud.CopyResult) {
+	switch result.Status {
+	case packagecloud.CopyStatusPlanned:
+		fmt.Printf("would copy %s to %s (%s)\n", result.Package.Filename, result.Destination, result.Distro)
+	case packagecloud.CopyStatusSkipped:
+		fmt.Printf("skipped %s, already exists in %s (%s)\n", result.Package.Filename, result.Destination, result.Distro)
+	case packagecloud.CopyStatusFailed:
+		fmt.Printf("failed to copy %s: %s\n", result.Package.Filename, result.Err)
+	default:
+		fmt.Printf("copied %s to %s (%s)\n", result.Package.Filename, result.Destination, result.Distro)
+	}
+}
+
+func printResults(format string, dryRun bool, results []packagecloud.CopyResult) error {
+	counts := map[packagecloud.CopyStatus]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			Results []packagecloud.CopyResult       `json:"results"`
+			Summary map[packagecloud.CopyStatus]int `json:"summary"`
+		}{
+			Results: results,
+			Summary: counts,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	fmt.Println("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Status", "Name", "Version", "Distro", "Architecture", "Filename", "Error"})
+	table.SetAutoMergeCells(false)
+
+	for _, result := range results {
+		var errMsg string
+		if result.Err != nil {
+			errMsg = result.Err.Error()
+		}
+		table.Append([]string{
+			string(result.Status),
+			result.Package.Name,
+			result.Package.Version,
+			result.Distro,
+			result.Package.Architecture,
+			result.Package.Filename,
+			errMsg,
+		})
+	}
+	table.Render()
+
+	copied := fmt.Sprintf("%d copied", counts[packagecloud.CopyStatusCopied])
+	if dryRun {
+		copied = fmt.Sprintf("%d would be copied", counts[packagecloud.CopyStatusPlanned])
+	}
+	fmt.Printf("\n%s, %d skipped, %d failed\n", copied,
+		counts[packagecloud.CopyStatusSkipped], counts[packagecloud.CopyStatusFailed])
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/output"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "table"
+
+	// formatUnified prints the differences in the style of a unified diff.
+	formatUnified = "unified"
+
+	flagQuery      = "query"
+	shortFlagQuery = "q"
+
+	flagFilter      = "filter"
+	shortFlagFilter = "i"
+
+	flagDist      = "dist"
+	shortFlagDist = "d"
+
+	flagArch      = "arch"
+	shortFlagArch = "a"
+
+	flagSkipChecksums = "skip-checksums"
+
+	defaultSkipChecksums = false
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+)
+
+var defaultColumns = []string{"status", "name", "version", "distro_version", "architecture", "filename"}
+
+func diffColumns() output.Columns {
+	columns := output.StructColumns(packagecloud.DiffEntry{})
+	for i := range columns {
+		switch columns[i].Name {
+		case "version":
+			columns[i].Compare = compareEntryVersions
+		case "distro_version":
+			columns[i].Header = "Distro"
+			columns[i].Aliases = []string{"distro"}
+		case "architecture":
+			columns[i].Aliases = []string{"arch"}
+		}
+	}
+	return columns
+}
+
+// compareEntryVersions compares the versions of two entries with the
+// version scheme of their package type.
+func compareEntryVersions(a, b interface{}) int {
+	entryA := a.(packagecloud.DiffEntry)
+	entryB := b.(packagecloud.DiffEntry)
+
+	if entryA.Type != entryB.Type {
+		return strings.Compare(entryA.Version, entryB.Version)
+	}
+	return types.CompareVersionsLenient(types.VersionSchemeForPackageType(entryA.Type), entryA.Version, entryB.Version)
+}
+
+func DiffCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var leftRepo, rightRepo packagecloud.Repo
+	var searchQuery *packagecloud.Query
+	columns := diffColumns()
+
+	cmd := &cobra.Command{
+		Use:   "diff <user/repo> <user/repo> [query]",
+		Short: "Show the packages that differ between two repositories",
+		Example: "diff ecorp/staging ecorp/production\n" +
+			"diff ecorp/staging ecorp/production 'name:ecorp-agent dist:ubuntu/*' -f unified",
+		Long: `Show the packages that differ between two repositories.
+
+Packages are matched by distro version and filename. A package is reported as
+left-only or right-only when it only exists in one of the repositories, and as
+changed when it exists in both with a different checksum. Comparing checksums
+retrieves the details of every package that exists in both repositories, use
+--skip-checksums to only compare which packages exist.
+
+The packages compared can be restricted with the search flags or a query (see
+the search command). Without either, every package of both repositories is
+compared.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) < 2 || len(args) > 3 {
+				return newErrWithUsage("requires 2 or 3 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid left repo: %s", err))
+			} else {
+				leftRepo = arg
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[1]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid right repo: %s", err))
+			} else {
+				rightRepo = arg
+			}
+
+			if len(args) == 3 {
+				q, err := packagecloud.ParseQuery(args[2], time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			query, err := cmd.Flags().GetString(flagQuery)
+			if err != nil {
+				return err
+			}
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			skipChecksums, err := cmd.Flags().GetBool(flagSkipChecksums)
+			if err != nil {
+				return err
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			options := packagecloud.SearchOptions{
+				Query:  query,
+				Filter: filter,
+				Dist:   dist,
+				Arch:   arch,
+			}
+			if searchQuery != nil {
+				searchQuery.Apply(&options)
+			}
+
+			var printer *output.Printer
+			if format != formatUnified {
+				printer, err = output.NewPrinterFromFlags(cmd, format, columns, defaultColumns, nil)
+				if err != nil {
+					return newErrWithUsage(err.Error())
+				}
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			diff, err := client.Diff(leftRepo, rightRepo, options, packagecloud.DiffOptions{
+				SkipChecksums: skipChecksums,
+				Concurrency:   concurrency,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to compare repositories: %s", err)
+			}
+
+			if format == formatUnified {
+				printUnified(os.Stdout, diff)
+				return nil
+			}
+
+			rows := make([]interface{}, 0, len(diff.Entries))
+			for _, entry := range diff.Entries {
+				rows = append(rows, entry)
+			}
+
+			if format == output.FormatTable {
+				if len(rows) > 0 {
+					if err := printer.Print(os.Stdout, rows); err != nil {
+						return err
+					}
+					fmt.Println("")
+				}
+				printSummary(diff)
+				return nil
+			}
+
+			return printer.Print(os.Stdout, rows)
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, strings.Replace(output.FormatUsage, "table,", "table, "+formatUnified+",", 1))
+	cmd.Flags().StringP(flagQuery, shortFlagQuery, "", "search string to search for package filename(s)")
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().Bool(flagSkipChecksums, defaultSkipChecksums, "don't compare the checksums of packages that exist in both repositories")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to compare the checksums of at the same time")
+	output.AddFlags(cmd, columns)
+
+	return cmd
+}
+
+// printUnified prints the differences in the style of a unified diff, with
+// a line for each package that only exists in the left (-) or right (+)
+// repository, and a pair of lines with the checksums of each changed
+// package.
+func printUnified(w io.Writer, diff *packagecloud.RepoDiff) {
+	fmt.Fprintf(w, "--- %s\n", diff.Left)
+	fmt.Fprintf(w, "+++ %s\n", diff.Right)
+
+	for _, entry := range diff.Entries {
+		line := strings.Join([]string{entry.Name, entry.Version, entry.DistroVersion, entry.Architecture, entry.Filename}, " ")
+		switch entry.Status {
+		case packagecloud.DiffStatusLeftOnly:
+			fmt.Fprintf(w, "-%s\n", line)
+		case packagecloud.DiffStatusRightOnly:
+			fmt.Fprintf(w, "+%s\n", line)
+		case packagecloud.DiffStatusChanged:
+			fmt.Fprintf(w, "-%s %s\n", line, entry.LeftChecksum)
+			fmt.Fprintf(w, "+%s %s\n", line, entry.RightChecksum)
+		}
+	}
+}
+
+func printSummary(diff *packagecloud.RepoDiff) {
+	counts := map[packagecloud.DiffStatus]int{}
+	for _, entry := range diff.Entries {
+		counts[entry.Status]++
+	}
+
+	fmt.Printf("%d only in %s, %d only in %s, %d changed, %d identical\n",
+		counts[packagecloud.DiffStatusLeftOnly], diff.Left,
+		counts[packagecloud.DiffStatusRightOnly], diff.Right,
+		counts[packagecloud.DiffStatusChanged],
+		diff.Same)
+}
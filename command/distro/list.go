@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"os"
 
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/output"
 	"github.com/amdprophet/packagecloud-go/packagecloud"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -17,27 +18,66 @@ const (
 	defaultFormat = "table"
 )
 
+// distroVersionRow is a row of the distro list output.
+type distroVersionRow struct {
+	PackageType        string `json:"package_type"`
+	Distro             string `json:"distro"`
+	DistroDisplayName  string `json:"distro_display_name"`
+	Version            string `json:"version"`
+	VersionDisplayName string `json:"version_display_name"`
+	VersionNumber      string `json:"version_number"`
+	ID                 int    `json:"id"`
+}
+
+func listColumns() output.Columns {
+	columns := output.StructColumns(distroVersionRow{})
+	columns[0].Header = "Package Type"
+
+	return columns.Set(output.Column{
+		Name:   "distro_version",
+		Header: "Distro/Version",
+		Value: func(row interface{}) interface{} {
+			r := row.(distroVersionRow)
+			return fmt.Sprintf("%s/%s", r.Distro, r.Version)
+		},
+	})
+}
+
 func ListCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	columns := listColumns()
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List available distros and versions for package_type",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClientFn()
-			if err != nil {
-				return err
-			}
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
 
 			format, err := cmd.Flags().GetString(flagFormat)
 			if err != nil {
 				return fmt.Errorf("failed to parse format: %s", err)
 			}
 
+			printer, err := output.NewPrinterFromFlags(cmd, format, columns,
+				[]string{"package_type", "distro_version"},
+				[]string{"package_type", "distro", "version_number", "version"})
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+			printer.MergeCells = true
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
 			packageTypes, err := client.GetDistributions()
 			if err != nil {
 				return fmt.Errorf("failed to retrieve distributions: %s", err)
 			}
 
-			if format == "json" {
+			// JSON output is the API response as is unless columns or sorting
+			// are asked for.
+			if format == output.FormatJSON && !printer.Selected() && !printer.Sorted() {
 				bytes, err := json.Marshal(packageTypes)
 				if err != nil {
 					return fmt.Errorf("failed to marshal packages: %w", err)
@@ -46,28 +86,29 @@ func ListCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return nil
 			}
 
-			table := tablewriter.NewWriter(os.Stdout)
-			table.SetHeader([]string{"Package Type", "Distro/Version"})
-			table.SetAutoMergeCells(true)
-
+			var rows []interface{}
 			for packageType, distros := range packageTypes {
 				for _, distro := range distros {
 					for _, distroVersion := range distro.Versions {
-						row := []string{
-							packageType,
-							fmt.Sprintf("%s/%s", distro.IndexName, distroVersion.IndexName),
-						}
-						table.Append(row)
+						rows = append(rows, distroVersionRow{
+							PackageType:        packageType,
+							Distro:             distro.IndexName,
+							DistroDisplayName:  distro.DisplayName,
+							Version:            distroVersion.IndexName,
+							VersionDisplayName: distroVersion.DisplayName,
+							VersionNumber:      distroVersion.VersionNumber,
+							ID:                 distroVersion.ID,
+						})
 					}
 				}
 			}
-			table.Render()
 
-			return nil
+			return printer.Print(os.Stdout, rows)
 		},
 	}
 
-	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - table or json")
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, output.FormatUsage)
+	output.AddFlags(cmd, columns)
 
 	return cmd
 }
@@ -0,0 +1,529 @@
+// Package output prints lists of results as a table, JSON, CSV, YAML,
+// newline-delimited JSON or with a Go template, with support for selecting
+// and sorting by columns.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	FormatTable  = "table"
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatYAML   = "yaml"
+	FormatNDJSON = "ndjson"
+
+	// FormatTemplate is the prefix of formats that print each row with a Go
+	// text/template (i.e. template={{.Name}} {{.Version}}).
+	FormatTemplate = "template="
+)
+
+const (
+	FlagColumns = "columns"
+
+	FlagSort = "sort"
+)
+
+// FormatUsage is the usage of the format flag of commands using a Printer.
+const FormatUsage = "output format to use - table, json, csv, yaml, ndjson or template=<go template> (i.e. 'template={{.Name}} {{.Version}}')"
+
+// Column is a value of each row that can be printed and sorted by.
+type Column struct {
+	// Name identifies the column in --columns and --sort, and is used as the
+	// key in JSON and YAML output and as the header of CSV output.
+	Name string
+
+	// Aliases are other names that can be used for the column.
+	Aliases []string
+
+	// Header is the header of the column in tables.
+	Header string
+
+	// Value returns the value of the column for a row.
+	Value func(row interface{}) interface{}
+
+	// Compare compares two rows by the column. If nil, rows are compared
+	// by their values.
+	Compare func(a, b interface{}) int
+}
+
+// Columns is the set of columns available for a type of row.
+type Columns []Column
+
+// StructColumns returns a column for each JSON field of the struct v, in
+// the order they are declared. Fields of embedded structs are included as if
+// they were fields of v, the same way encoding/json marshals them.
+func StructColumns(v interface{}) Columns {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return structColumns(t, nil)
+}
+
+func structColumns(t reflect.Type, index []int) Columns {
+	var columns Columns
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			columns = append(columns, structColumns(field.Type, fieldIndex)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, Column{
+			Name:   name,
+			Header: strings.ReplaceAll(name, "_", " "),
+			Value: func(row interface{}) interface{} {
+				v := reflect.ValueOf(row)
+				for v.Kind() == reflect.Ptr {
+					v = v.Elem()
+				}
+				return v.FieldByIndex(fieldIndex).Interface()
+			},
+		})
+	}
+	return columns
+}
+
+// Get returns the column with the given name or alias.
+func (cs Columns) Get(name string) (Column, bool) {
+	name = strings.ToLower(name)
+	for _, c := range cs {
+		if c.Name == name {
+			return c, true
+		}
+		for _, alias := range c.Aliases {
+			if alias == name {
+				return c, true
+			}
+		}
+	}
+	return Column{}, false
+}
+
+// Set replaces the column with the same name as c, or adds it if there is
+// none.
+func (cs Columns) Set(c Column) Columns {
+	for i := range cs {
+		if cs[i].Name == c.Name {
+			cs[i] = c
+			return cs
+		}
+	}
+	return append(cs, c)
+}
+
+// Names returns the names of the columns.
+func (cs Columns) Names() []string {
+	names := make([]string, 0, len(cs))
+	for _, c := range cs {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+type sortKey struct {
+	column     Column
+	descending bool
+}
+
+// Printer prints rows in the selected format.
+type Printer struct {
+	// MergeCells merges identical adjacent cells in tables.
+	MergeCells bool
+
+	format   string
+	columns  Columns
+	selected bool
+	sortKeys []sortKey
+	template *template.Template
+	sorted   bool
+}
+
+// NewPrinter returns a printer for format. Columns are the names of the
+// columns to print, and sortKeys the names of the columns to sort by,
+// prefixed with - to sort in descending order. If columns is empty,
+// defaultColumns are printed in tables and CSV, and every column is
+// included in JSON, NDJSON and YAML.
+func NewPrinter(format string, available Columns, defaultColumns, columns, sortKeys []string) (*Printer, error) {
+	p := &Printer{format: format}
+
+	switch {
+	case format == FormatTable, format == FormatJSON, format == FormatCSV, format == FormatYAML, format == FormatNDJSON:
+	case strings.HasPrefix(format, FormatTemplate):
+		tmpl, err := template.New("output").Parse(strings.TrimPrefix(format, FormatTemplate) + "\n")
+		if err != nil {
+			return nil, fmt.Errorf("invalid output template: %w", err)
+		}
+		p.template = tmpl
+	default:
+		return nil, fmt.Errorf("invalid output format %q (must be one of: table, json, csv, yaml, ndjson, template=<go template>)", format)
+	}
+
+	names := columns
+	p.selected = len(columns) > 0
+	if !p.selected {
+		switch format {
+		case FormatTable, FormatCSV:
+			names = defaultColumns
+		default:
+			names = available.Names()
+		}
+	}
+	for _, name := range names {
+		c, ok := available.Get(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("invalid column %q (must be one of: %s)", name, strings.Join(available.Names(), ", "))
+		}
+		p.columns = append(p.columns, c)
+	}
+
+	for _, key := range sortKeys {
+		key = strings.TrimSpace(key)
+		descending := strings.HasPrefix(key, "-")
+		c, ok := available.Get(strings.TrimPrefix(key, "-"))
+		if !ok {
+			return nil, fmt.Errorf("invalid sort column %q (must be one of: %s)", key, strings.Join(available.Names(), ", "))
+		}
+		p.sortKeys = append(p.sortKeys, sortKey{column: c, descending: descending})
+	}
+
+	return p, nil
+}
+
+// AddFlags adds the --columns and --sort flags to cmd.
+func AddFlags(cmd *cobra.Command, available Columns) {
+	names := strings.Join(available.Names(), ", ")
+	cmd.Flags().StringSlice(FlagColumns, nil, "columns to print - "+names)
+	cmd.Flags().StringSlice(FlagSort, nil, "columns to sort by, prefixed with - for descending order (i.e. name,-version)")
+}
+
+// NewPrinterFromFlags returns a printer for the format, --columns and --sort
+// flags of cmd. Rows are sorted by defaultSort when --sort is not set.
+func NewPrinterFromFlags(cmd *cobra.Command, format string, available Columns, defaultColumns, defaultSort []string) (*Printer, error) {
+	columns, err := cmd.Flags().GetStringSlice(FlagColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	sortKeys, err := cmd.Flags().GetStringSlice(FlagSort)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := len(sortKeys) > 0
+	if !sorted {
+		sortKeys = defaultSort
+	}
+
+	p, err := NewPrinter(format, available, defaultColumns, columns, sortKeys)
+	if err != nil {
+		return nil, err
+	}
+	p.sorted = sorted
+
+	return p, nil
+}
+
+// Selected returns whether or not columns were selected explicitly, as
+// opposed to printing the default columns.
+func (p *Printer) Selected() bool {
+	return p.selected
+}
+
+// Sorted returns whether or not sort columns were set explicitly, as opposed
+// to using the default sort order.
+func (p *Printer) Sorted() bool {
+	return p.sorted
+}
+
+// Streaming returns whether or not rows can be printed as they arrive with
+// PrintRow instead of all at once with Print. This is the case for NDJSON
+// output when rows were not explicitly asked to be sorted.
+func (p *Printer) Streaming() bool {
+	return p.format == FormatNDJSON && !p.sorted
+}
+
+// Sort sorts rows by the printer's sort columns.
+func (p *Printer) Sort(rows []interface{}) {
+	if len(p.sortKeys) == 0 {
+		return
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, key := range p.sortKeys {
+			c := compareRows(key.column, rows[i], rows[j])
+			if key.descending {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// Print sorts and prints rows to w.
+func (p *Printer) Print(w io.Writer, rows []interface{}) error {
+	p.Sort(rows)
+
+	switch {
+	case p.format == FormatTable:
+		p.printTable(w, rows)
+		return nil
+	case p.format == FormatCSV:
+		return p.printCSV(w, rows)
+	case p.format == FormatJSON:
+		return p.printJSON(w, rows)
+	case p.format == FormatYAML:
+		return p.printYAML(w, rows)
+	}
+
+	for _, row := range rows {
+		if err := p.PrintRow(w, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintRow prints a single row to w in NDJSON or template formats.
+func (p *Printer) PrintRow(w io.Writer, row interface{}) error {
+	if p.template != nil {
+		if err := p.template.Execute(w, row); err != nil {
+			return fmt.Errorf("failed to execute output template: %w", err)
+		}
+		return nil
+	}
+
+	if p.format != FormatNDJSON {
+		return fmt.Errorf("cannot print a single row in %s format", p.format)
+	}
+
+	record, err := p.marshalJSON(row)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(record))
+	return err
+}
+
+func (p *Printer) printTable(w io.Writer, rows []interface{}) {
+	header := make([]string, 0, len(p.columns))
+	for _, c := range p.columns {
+		header = append(header, c.Header)
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAutoMergeCells(p.MergeCells)
+
+	for _, row := range rows {
+		table.Append(p.cells(row))
+	}
+	table.Render()
+}
+
+func (p *Printer) printCSV(w io.Writer, rows []interface{}) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, 0, len(p.columns))
+	for _, c := range p.columns {
+		header = append(header, c.Name)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := writer.Write(p.cells(row)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (p *Printer) printJSON(w io.Writer, rows []interface{}) error {
+	records := make([]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		record, err := p.marshalJSON(row)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+
+	out, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+func (p *Printer) printYAML(w io.Writer, rows []interface{}) error {
+	records := make([]yaml.MapSlice, 0, len(rows))
+	for _, row := range rows {
+		record := yaml.MapSlice{}
+		for _, c := range p.columns {
+			record = append(record, yaml.MapItem{Key: c.Name, Value: yamlValue(c.Value(row))})
+		}
+		records = append(records, record)
+	}
+
+	out, err := yaml.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// marshalJSON marshals the printer's columns of row as a JSON object, with
+// the keys in column order.
+func (p *Printer) marshalJSON(row interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range p.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(c.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal output: %w", err)
+		}
+		value, err := json.Marshal(c.Value(row))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal output: %w", err)
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (p *Printer) cells(row interface{}) []string {
+	cells := make([]string, 0, len(p.columns))
+	for _, c := range p.columns {
+		cells = append(cells, FormatValue(c.Value(row)))
+	}
+	return cells
+}
+
+// FormatValue formats a column value for tables and CSV.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ", ")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// yamlValue converts values that yaml.v2 can't marshal the way they are
+// marshaled to JSON.
+func yamlValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return FormatValue(t)
+	}
+	return v
+}
+
+func compareRows(c Column, a, b interface{}) int {
+	if c.Compare != nil {
+		return c.Compare(a, b)
+	}
+	return CompareValues(c.Value(a), c.Value(b))
+}
+
+// CompareValues compares two column values of the same type. Numbers are
+// compared numerically, times chronologically, false sorts before true and
+// everything else is compared as formatted by FormatValue.
+func CompareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		if b, ok := b.(int); ok {
+			return compareInts(int64(a), int64(b))
+		}
+	case int64:
+		if b, ok := b.(int64); ok {
+			return compareInts(a, b)
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			return strings.Compare(strconv.FormatBool(a), strconv.FormatBool(b))
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	}
+	return strings.Compare(FormatValue(a), FormatValue(b))
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
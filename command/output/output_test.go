@@ -0,0 +1,168 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRow struct {
+	Name      string    `json:"name"`
+	Downloads int       `json:"downloads"`
+	Tags      []string  `json:"tags"`
+	Created   time.Time `json:"created_at"`
+	internal  string
+}
+
+func testRows() []interface{} {
+	return []interface{}{
+		testRow{Name: "b", Downloads: 10, Tags: []string{"x", "y"}, Created: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		testRow{Name: "a", Downloads: 9, Created: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		testRow{Name: "a", Downloads: 100},
+	}
+}
+
+func printRows(t *testing.T, format string, columns, sortKeys []string) string {
+	t.Helper()
+
+	p, err := NewPrinter(format, StructColumns(testRow{}), []string{"name", "downloads"}, columns, sortKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Print(&buf, testRows()); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestStructColumns(t *testing.T) {
+	columns := StructColumns(&testRow{})
+
+	expected := []string{"name", "downloads", "tags", "created_at"}
+	if got := strings.Join(columns.Names(), ","); got != strings.Join(expected, ",") {
+		t.Errorf("expected columns %v, got %s", expected, got)
+	}
+
+	c, ok := columns.Get("downloads")
+	if !ok {
+		t.Fatal("expected downloads column")
+	}
+	if got := c.Value(&testRow{Downloads: 3}); got != 3 {
+		t.Errorf("expected 3, got %v", got)
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	expected := "name,downloads\n" +
+		"a,100\n" +
+		"a,9\n" +
+		"b,10\n"
+	if got := printRows(t, FormatCSV, nil, []string{"name", "-downloads"}); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestPrintCSVColumns(t *testing.T) {
+	expected := "tags,created_at\n" +
+		"\"x, y\",2024-01-02T00:00:00Z\n" +
+		",2024-01-03T00:00:00Z\n" +
+		",\n"
+	if got := printRows(t, FormatCSV, []string{"tags", "created_at"}, nil); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestPrintJSON(t *testing.T) {
+	expected := `[{"downloads":9,"name":"a"},{"downloads":10,"name":"b"},{"downloads":100,"name":"a"}]` + "\n"
+	if got := printRows(t, FormatJSON, []string{"downloads", "name"}, []string{"downloads"}); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestPrintNDJSON(t *testing.T) {
+	got := printRows(t, FormatNDJSON, nil, []string{"created_at"})
+
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d:\n%s", len(lines), got)
+	}
+	expected := `{"name":"a","downloads":100,"tags":null,"created_at":"0001-01-01T00:00:00Z"}`
+	if lines[0] != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, lines[0])
+	}
+}
+
+func TestPrintYAML(t *testing.T) {
+	expected := "- name: b\n" +
+		"  tags:\n" +
+		"  - x\n" +
+		"  - \"y\"\n"
+	got := printRows(t, FormatYAML, []string{"name", "tags"}, []string{"-name"})
+	if !strings.HasPrefix(got, expected) {
+		t.Errorf("expected prefix:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestPrintTemplate(t *testing.T) {
+	expected := "a 100\na 9\nb 10\n"
+	if got := printRows(t, FormatTemplate+"{{.Name}} {{.Downloads}}", nil, []string{"name", "-downloads"}); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestNewPrinterErrors(t *testing.T) {
+	columns := StructColumns(testRow{})
+
+	tests := []struct {
+		name     string
+		format   string
+		columns  []string
+		sortKeys []string
+		expected string
+	}{
+		{"format", "xml", nil, nil, "invalid output format"},
+		{"template", FormatTemplate + "{{.Name", nil, nil, "invalid output template"},
+		{"column", FormatTable, []string{"size"}, nil, `invalid column "size"`},
+		{"sort", FormatTable, nil, []string{"-size"}, `invalid sort column "-size"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPrinter(tt.format, columns, nil, tt.columns, tt.sortKeys)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestColumnAliases(t *testing.T) {
+	columns := StructColumns(testRow{})
+	columns[1].Aliases = []string{"dl"}
+
+	if _, ok := columns.Get("DL"); !ok {
+		t.Error("expected to find column by alias")
+	}
+}
+
+func TestStructColumnsEmbedded(t *testing.T) {
+	type embeddedRow struct {
+		Repository string `json:"repository"`
+		testRow
+	}
+
+	columns := StructColumns(embeddedRow{})
+
+	expected := []string{"repository", "name", "downloads", "tags", "created_at"}
+	if got := strings.Join(columns.Names(), ","); got != strings.Join(expected, ",") {
+		t.Errorf("expected columns %v, got %s", expected, got)
+	}
+
+	c, _ := columns.Get("name")
+	if got := c.Value(embeddedRow{testRow: testRow{Name: "a"}}); got != "a" {
+		t.Errorf("expected a, got %v", got)
+	}
+}
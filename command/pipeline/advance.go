@@ -0,0 +1,184 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/pipeline"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "text"
+
+	flagFile = "file"
+
+	defaultFile = "pipeline.yaml"
+
+	flagApprove = "approve"
+
+	defaultApprove = false
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+
+	flagContinueOnError = "continue-on-error"
+
+	defaultContinueOnError = false
+)
+
+func AdvanceCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var packageName string
+	var version string
+
+	cmd := &cobra.Command{
+		Use:     "advance <package name> <version>",
+		Short:   "Promote a package version to the next stage of the pipeline if every gate passes",
+		Example: "advance ecorp-agent 3.2.0 --file pipeline.yaml",
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 2 {
+				return newErrWithUsage("requires exactly 2 arguments")
+			}
+
+			packageName = args[0]
+			version = args[1]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := cmd.Flags().GetString(flagFile)
+			if err != nil {
+				return err
+			}
+
+			approve, err := cmd.Flags().GetBool(flagApprove)
+			if err != nil {
+				return err
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			p, err := pipeline.Load(file)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			result, err := p.Advance(client, pipeline.AdvanceOptions{
+				PackageName: packageName,
+				Version:     version,
+				Approved:    approve,
+				Promote: packagecloud.PromoteOptions{
+					Concurrency:     concurrency,
+					ContinueOnError: continueOnError,
+				},
+			})
+			if result != nil {
+				if err := printResult(format, result); err != nil {
+					return err
+				}
+			}
+			if errors.Is(err, pipeline.ErrGatesFailed) {
+				return fmt.Errorf("cannot advance %s %s from %s to %s: %s", packageName, version, result.From.Name, result.To.Name, err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to advance %s %s: %s", packageName, version, err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
+	cmd.Flags().String(flagFile, defaultFile, "path to the pipeline definition")
+	cmd.Flags().Bool(flagApprove, defaultApprove, "approve the promotion for stages that require manual approval")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to promote at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep promoting the remaining packages when a promotion fails")
+
+	return cmd
+}
+
+func printResult(format string, result *pipeline.AdvanceResult) error {
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			From       string                       `json:"from"`
+			To         string                       `json:"to"`
+			Gates      []pipeline.GateResult        `json:"gates"`
+			Promotions []packagecloud.PromoteResult `json:"promotions"`
+		}{
+			From:       result.From.Name,
+			To:         result.To.Name,
+			Gates:      result.Gates,
+			Promotions: result.Promotions,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal result: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	fmt.Printf("Advancing from %s (%s) to %s (%s)\n\n", result.From.Name, result.From.Repo, result.To.Name, result.To.Repo)
+
+	if len(result.Gates) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Gate", "Passed", "Message"})
+		table.SetAutoMergeCells(false)
+		for _, gate := range result.Gates {
+			table.Append([]string{gate.Gate, strconv.FormatBool(gate.Passed), gate.Message})
+		}
+		table.Render()
+		fmt.Println("")
+	}
+
+	if len(result.Promotions) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Status", "Distro", "Architecture", "Filename", "Error"})
+		table.SetAutoMergeCells(false)
+		for _, promotion := range result.Promotions {
+			var errMsg string
+			if promotion.Err != nil {
+				errMsg = promotion.Err.Error()
+			}
+			table.Append([]string{
+				string(promotion.Status),
+				promotion.Package.DistroVersion,
+				promotion.Package.Architecture,
+				promotion.Package.Filename,
+				errMsg,
+			})
+		}
+		table.Render()
+	}
+
+	return nil
+}
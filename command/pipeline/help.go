@@ -0,0 +1,17 @@
+package pipeline
+
+import (
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/spf13/cobra"
+)
+
+func HelpCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "Promote packages through a multi-stage pipeline",
+	}
+
+	cmd.AddCommand(AdvanceCommand(getClientFn))
+
+	return cmd
+}
@@ -72,13 +72,28 @@ func ByFilenameCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return err
 			}
 
-			if err := client.PromoteByFilename(srcRepo, dstRepo, distro, filename); err != nil {
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			promoteOptions := packagecloud.PromoteOptions{
+				Observer: newObserver(format),
+			}
+
+			result, err := client.PromoteByFilename(srcRepo, dstRepo, distro, filename, promoteOptions)
+			if err != nil {
+				if result.Status != "" {
+					printResults(format, client.DryRun(), []packagecloud.PromoteResult{result})
+				}
 				return fmt.Errorf("failed to promote package: %s", err)
 			}
 
-			return nil
+			return printResults(format, client.DryRun(), []packagecloud.PromoteResult{result})
 		},
 	}
 
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
+
 	return cmd
 }
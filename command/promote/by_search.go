@@ -2,6 +2,7 @@ package promote
 
 import (
 	"fmt"
+	"time"
 
 	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
 	"github.com/amdprophet/packagecloud-go/packagecloud"
@@ -9,6 +10,19 @@ import (
 )
 
 const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "text"
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+
+	flagContinueOnError = "continue-on-error"
+
+	defaultContinueOnError = false
+
 	flagQuery      = "query"
 	shortFlagQuery = "q"
 
@@ -25,30 +39,31 @@ const (
 func BySearchCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 	var srcRepo packagecloud.Repo
 	var dstRepo packagecloud.Repo
+	var searchQuery *packagecloud.Query
 
 	name := "by-search"
 	usage := fmt.Sprintf("%s <%s> <%s> (%s)",
 		name,
 		"source repository",
 		"destination repository",
-		"-q | -i | -d | -a",
+		"-q | -i | -d | -a | query",
 	)
 	example := fmt.Sprintf("%s %s %s %s",
 		name,
 		"ecorp/staging",
 		"ecorp/production",
-		"-q '1.4.3-3258'",
+		"'name:ecorp-agent version:1.4.3-3258 dist:ubuntu/*'",
 	)
 
 	cmd := &cobra.Command{
 		Use:     usage,
-		Short:   "Search for packages matching search options and promote all matches",
+		Short:   "Search for packages matching search options or a query and promote all matches",
 		Example: example,
 		Args: func(cmd *cobra.Command, args []string) error {
 			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
 
-			if len(args) != 2 {
-				return newErrWithUsage("requires exactly 2 arguments")
+			if len(args) < 2 || len(args) > 3 {
+				return newErrWithUsage("requires 2 or 3 arguments")
 			}
 
 			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
@@ -65,6 +80,14 @@ func BySearchCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				dstRepo = arg
 			}
 
+			if len(args) == 3 {
+				q, err := packagecloud.ParseQuery(args[2], time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+			}
+
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -103,20 +126,51 @@ func BySearchCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				Dist:     dist,
 				Arch:     arch,
 			}
+			if searchQuery != nil {
+				searchQuery.Apply(&options)
+			}
 
 			if err := options.Validate(); err != nil {
 				return newErrWithUsage(err.Error())
 			}
 
-			if err := client.PromoteBySearch(dstRepo, options); err != nil {
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			promoteOptions := packagecloud.PromoteOptions{
+				Observer:        newObserver(format),
+				Concurrency:     concurrency,
+				ContinueOnError: continueOnError,
+			}
+
+			results, err := client.PromoteBySearch(dstRepo, options, promoteOptions)
+			if err != nil {
+				if len(results) > 0 {
+					printResults(format, client.DryRun(), results)
+				}
 				return fmt.Errorf("failed to promote packages: %s", err)
 			}
 
-			return nil
+			return printResults(format, client.DryRun(), results)
 		},
 	}
 
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
 	cmd.Flags().StringP(flagQuery, shortFlagQuery, "", "search string to search for package filename(s)")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to promote at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep promoting the remaining packages when a promotion fails")
 	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
 	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
 	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
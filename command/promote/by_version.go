@@ -0,0 +1,220 @@
+package promote
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/prompt"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagYes      = "yes"
+	shortFlagYes = "y"
+
+	flagIncludePrereleases = "include-prereleases"
+
+	defaultIncludePrereleases = false
+)
+
+func ByVersionCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var srcRepo packagecloud.Repo
+	var dstRepo packagecloud.Repo
+	var packageName string
+	var constraint string
+
+	name := "by-version"
+	usage := fmt.Sprintf("%s <%s> <%s> <%s> <%s>",
+		name,
+		"source repository",
+		"destination repository",
+		"package name",
+		"version constraint",
+	)
+	example := fmt.Sprintf("%s %s %s %s %s",
+		name,
+		"ecorp/staging",
+		"ecorp/production",
+		"ecorp-agent",
+		"'>=2.3.0 <2.4.0'",
+	)
+
+	cmd := &cobra.Command{
+		Use:     usage,
+		Short:   "Promote every package with a given name whose version matches a constraint",
+		Example: example,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 4 {
+				return newErrWithUsage("requires exactly 4 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				msg := fmt.Sprintf("invalid source repo: %s", err)
+				return newErrWithUsage(msg)
+			} else {
+				srcRepo = arg
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[1]); err != nil {
+				msg := fmt.Sprintf("invalid destination repo: %s", err)
+				return newErrWithUsage(msg)
+			} else {
+				dstRepo = arg
+			}
+
+			packageName = args[2]
+			constraint = args[3]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			yes, err := cmd.Flags().GetBool(flagYes)
+			if err != nil {
+				return err
+			}
+
+			includePrereleases, err := cmd.Flags().GetBool(flagIncludePrereleases)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			options := packagecloud.ListVersionsOptions{
+				Repo:               srcRepo,
+				PackageName:        packageName,
+				Filter:             filter,
+				Dist:               dist,
+				Arch:               arch,
+				IncludePrereleases: includePrereleases,
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			if _, err := types.ParseVersionConstraint(constraint); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			if format == "json" && !yes && !client.DryRun() {
+				return newErrWithUsage("--yes is required when using --format json")
+			}
+
+			packages, err := client.FindPackagesByVersion(options, constraint)
+			if err != nil {
+				return fmt.Errorf("failed to find packages: %s", err)
+			}
+			if len(packages) == 0 {
+				return fmt.Errorf("no %s packages in %s match version constraint: %s", packageName, srcRepo, constraint)
+			}
+
+			if format != "json" {
+				printMatches(packages)
+			}
+
+			if !yes && !client.DryRun() {
+				question := fmt.Sprintf("Promote %d package(s) from %s to %s?", len(packages), srcRepo, dstRepo)
+				ok, err := prompt.Confirm(question)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("promotion cancelled")
+				}
+			}
+
+			promoteOptions := packagecloud.PromoteOptions{
+				Observer:        newObserver(format),
+				Concurrency:     concurrency,
+				ContinueOnError: continueOnError,
+			}
+
+			results, err := client.PromotePackages(srcRepo, dstRepo, packages, promoteOptions)
+			if err != nil {
+				if len(results) > 0 {
+					printResults(format, client.DryRun(), results)
+				}
+				return fmt.Errorf("failed to promote packages: %s", err)
+			}
+
+			return printResults(format, client.DryRun(), results)
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to promote at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep promoting the remaining packages when a promotion fails")
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().BoolP(flagYes, shortFlagYes, false, "promote the matching packages without asking for confirmation")
+	cmd.Flags().Bool(flagIncludePrereleases, defaultIncludePrereleases, "also promote pre-release versions that match the constraint")
+
+	return cmd
+}
+
+// printMatches prints a table of the packages that are about to be promoted.
+func printMatches(packages types.PackageFragments) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Distro", "Version", "Release", "Epoch", "Architecture", "Filename"})
+	table.SetAutoMergeCells(false)
+
+	for _, pkg := range packages {
+		row := []string{
+			pkg.Name,
+			pkg.DistroVersion,
+			pkg.Version,
+			pkg.Release,
+			strconv.Itoa(pkg.Epoch),
+			pkg.Architecture,
+			pkg.Filename,
+		}
+		table.Append(row)
+	}
+	table.Render()
+	fmt.Println("")
+}
@@ -13,6 +13,7 @@ func HelpCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 
 	cmd.AddCommand(ByFilenameCommand(getClientFn))
 	cmd.AddCommand(BySearchCommand(getClientFn))
+	cmd.AddCommand(ByVersionCommand(getClientFn))
 
 	return cmd
 }
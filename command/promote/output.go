@@ -0,0 +1,160 @@
+package promote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/olekukonko/tablewriter"
+)
+
+// textObserver prints the outcome of each promotion in a human-readable form
+// as soon as it finishes.
+type textObserver struct {
+	mu *sync.Mutex
+}
+
+func (o textObserver) PromoteStarted(src packagecloud.Repo, dst packagecloud.Repo, pkg types.PackageFragment) {
+}
+
+func (o textObserver) PromoteFinished(result packagecloud.PromoteResult) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	pkg := result.Package
+
+	switch result.Status {
+	case packagecloud.PromoteStatusPlanned:
+		fmt.Println("Would promote package")
+	case packagecloud.PromoteStatusExists:
+		fmt.Println("Package already exists")
+	case packagecloud.PromoteStatusSkipped:
+		fmt.Println("Skipped package")
+	case packagecloud.PromoteStatusFailed:
+		fmt.Println("Failed to promote package")
+	default:
+		fmt.Println("Promoted package")
+	}
+	fmt.Printf("  - Source repository:      %s\n", result.Source)
+	fmt.Printf("  - Destination repository: %s\n", result.Destination)
+
+	fmt.Printf("  - Name:                   %s\n", pkg.Name)
+	fmt.Printf("  - Type:                   %s\n", pkg.Type)
+	fmt.Printf("  - Version:                %s\n", pkg.Version)
+	fmt.Printf("  - Release:                %s\n", pkg.Release)
+	fmt.Printf("  - Epoch:                  %d\n", pkg.Epoch)
+	fmt.Printf("  - Architecture:           %s\n", pkg.Architecture)
+	fmt.Printf("  - Distro:                 %s\n", pkg.DistroVersion)
+	fmt.Printf("  - Filename:               %s\n", pkg.Filename)
+
+	if result.Exists {
+		fmt.Println("  - Exists:                 package already exists in destination repository")
+	}
+	if result.Err != nil {
+		fmt.Printf("  - Error:                  %s\n", result.Err)
+	}
+	fmt.Println("")
+}
+
+// newObserver returns the observer to use for the given output format.
+func newObserver(format string) packagecloud.PromoteObserver {
+	if format == "json" {
+		return nil
+	}
+	return textObserver{
+		mu: &sync.Mutex{},
+	}
+}
+
+type summary struct {
+	Promoted int `json:"promoted"`
+	Planned  int `json:"planned"`
+	Exists   int `json:"exists"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+func summarize(results []packagecloud.PromoteResult) summary {
+	var s summary
+	for _, result := range results {
+		switch result.Status {
+		case packagecloud.PromoteStatusPromoted:
+			s.Promoted++
+		case packagecloud.PromoteStatusPlanned:
+			s.Planned++
+		case packagecloud.PromoteStatusExists:
+			s.Exists++
+		case packagecloud.PromoteStatusSkipped:
+			s.Skipped++
+		case packagecloud.PromoteStatusFailed:
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// printResults prints a summary of the outcome of a promotion in the given
+// format.
+func printResults(format string, dryRun bool, results []packagecloud.PromoteResult) error {
+	s := summarize(results)
+
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			Results []packagecloud.PromoteResult `json:"results"`
+			Summary summary                      `json:"summary"`
+		}{
+			Results: results,
+			Summary: s,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Status", "Name", "Version", "Distro", "Architecture", "Filename", "Error"})
+	table.SetAutoMergeCells(false)
+
+	for _, result := range results {
+		var errMsg string
+		if result.Err != nil {
+			errMsg = result.Err.Error()
+		}
+		status := string(result.Status)
+		if result.Exists && result.Status != packagecloud.PromoteStatusExists {
+			status += " (exists)"
+		}
+		row := []string{
+			status,
+			result.Package.Name,
+			result.Package.Version,
+			result.Package.DistroVersion,
+			result.Package.Architecture,
+			result.Package.Filename,
+			errMsg,
+		}
+		table.Append(row)
+	}
+	table.Render()
+
+	counts := []string{}
+	if dryRun {
+		counts = append(counts, fmt.Sprintf("%d would be promoted", s.Planned))
+	} else {
+		counts = append(counts, fmt.Sprintf("%d promoted", s.Promoted))
+	}
+	counts = append(counts,
+		fmt.Sprintf("%d already exist", s.Exists),
+		fmt.Sprintf("%d skipped", s.Skipped),
+		fmt.Sprintf("%d failed", s.Failed),
+	)
+	fmt.Printf("\n%s\n", strings.Join(counts, ", "))
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package prune
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/olekukonko/tablewriter"
+)
+
+// keepReasons are the reasons packages are kept, in the order they are
+// reported.
+var keepReasons = []packagecloud.PruneKeepReason{
+	packagecloud.PruneKeepNewest,
+	packagecloud.PruneKeepProtected,
+	packagecloud.PruneKeepRecent,
+	packagecloud.PruneKeepInvalidVersion,
+}
+
+// jsonPlan is the json representation of a prune plan.
+type jsonPlan struct {
+	Repository string                               `json:"repository"`
+	Delete     types.PackageFragments               `json:"delete"`
+	Kept       map[packagecloud.PruneKeepReason]int `json:"kept"`
+	Groups     int                                  `json:"groups"`
+}
+
+// printPlan prints the packages that are about to be deleted and how many
+// packages are kept to w in the given format.
+func printPlan(w io.Writer, format string, plan *packagecloud.PrunePlan) error {
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			Plan jsonPlan `json:"plan"`
+		}{
+			Plan: jsonPlan{
+				Repository: plan.Repo.String(),
+				Delete:     plan.Delete,
+				Kept:       plan.Kept,
+				Groups:     plan.Groups,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal plan: %w", err)
+		}
+		fmt.Fprintln(w, string(bytes))
+		return nil
+	}
+
+	if len(plan.Delete) > 0 {
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Name", "Distro", "Version", "Release", "Epoch", "Architecture", "Uploaded", "Filename"})
+		table.SetAutoMergeCells(false)
+
+		for _, pkg := range plan.Delete {
+			table.Append([]string{
+				pkg.Name,
+				pkg.DistroVersion,
+				pkg.Version,
+				pkg.Release,
+				strconv.Itoa(pkg.Epoch),
+				pkg.Architecture,
+				pkg.CreatedAt,
+				pkg.Filename,
+			})
+		}
+		table.Render()
+		fmt.Fprintln(w, "")
+	}
+
+	kept := []string{}
+	for _, reason := range keepReasons {
+		if n := plan.Kept[reason]; n > 0 {
+			kept = append(kept, fmt.Sprintf("%d %s", n, reason))
+		}
+	}
+	if len(kept) == 0 {
+		kept = append(kept, "none")
+	}
+
+	fmt.Fprintf(w, "%d package(s) to delete from %s in %d group(s), kept: %s\n\n",
+		len(plan.Delete), plan.Repo, plan.Groups, strings.Join(kept, ", "))
+
+	return nil
+}
+
+// printResults prints a summary of the outcome of a prune in the given
+// format. The text format only lists the packages that were not deleted,
+// since the others have already been listed by printPlan.
+func printResults(format string, dryRun bool, plan *packagecloud.PrunePlan, results []packagecloud.PruneResult) error {
+	counts := map[packagecloud.PruneStatus]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			Results []packagecloud.PruneResult           `json:"results"`
+			Summary map[packagecloud.PruneStatus]int     `json:"summary"`
+			Kept    map[packagecloud.PruneKeepReason]int `json:"kept"`
+		}{
+			Results: results,
+			Summary: counts,
+			Kept:    plan.Kept,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	if counts[packagecloud.PruneStatusFailed] > 0 || counts[packagecloud.PruneStatusSkipped] > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Status", "Name", "Version", "Distro", "Architecture", "Filename", "Error"})
+		table.SetAutoMergeCells(false)
+
+		for _, result := range results {
+			if result.Status != packagecloud.PruneStatusFailed && result.Status != packagecloud.PruneStatusSkipped {
+				continue
+			}
+			var errMsg string
+			if result.Err != nil {
+				errMsg = result.Err.Error()
+			}
+			table.Append([]string{
+				string(result.Status),
+				result.Package.Name,
+				result.Package.Version,
+				result.Package.DistroVersion,
+				result.Package.Architecture,
+				result.Package.Filename,
+				errMsg,
+			})
+		}
+		table.Render()
+		fmt.Println("")
+	}
+
+	deleted := fmt.Sprintf("%d deleted", counts[packagecloud.PruneStatusDeleted])
+	if dryRun {
+		deleted = fmt.Sprintf("%d would be deleted", counts[packagecloud.PruneStatusPlanned])
+	}
+	fmt.Printf("%s, %d skipped, %d failed\n", deleted,
+		counts[packagecloud.PruneStatusSkipped], counts[packagecloud.PruneStatusFailed])
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package prune
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+func TestPrintPlanJSON(t *testing.T) {
+	plan := &packagecloud.PrunePlan{
+		Repo: packagecloud.NewRepo("ecorp", "nightly"),
+		Delete: types.PackageFragments{
+			{Name: "agent", Version: "1.0.0", DistroVersion: "ubuntu/jammy", Filename: "agent_1.0.0-1_amd64.deb"},
+		},
+		Kept:   map[packagecloud.PruneKeepReason]int{packagecloud.PruneKeepNewest: 2},
+		Groups: 1,
+	}
+
+	var buf bytes.Buffer
+	if err := printPlan(&buf, "json", plan); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Plan jsonPlan `json:"plan"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected json, got %q: %s", buf.String(), err)
+	}
+
+	expected := jsonPlan{
+		Repository: "ecorp/nightly",
+		Delete:     plan.Delete,
+		Kept:       plan.Kept,
+		Groups:     1,
+	}
+	if !reflect.DeepEqual(got.Plan, expected) {
+		t.Errorf("expected plan %+v, got %+v", expected, got.Plan)
+	}
+}
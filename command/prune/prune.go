@@ -0,0 +1,316 @@
+package prune
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/prompt"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "text"
+
+	flagKeep = "keep"
+
+	flagPer = "per"
+
+	flagOlderThan = "older-than"
+
+	flagPackage = "package"
+
+	flagProtect = "protect"
+
+	flagMaxDeletions = "max-deletions"
+
+	defaultMaxDeletions = 50
+
+	flagScheme = "scheme"
+
+	flagFilter      = "filter"
+	shortFlagFilter = "i"
+
+	flagDist      = "dist"
+	shortFlagDist = "d"
+
+	flagArch      = "arch"
+	shortFlagArch = "a"
+
+	flagYes      = "yes"
+	shortFlagYes = "y"
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+
+	flagContinueOnError = "continue-on-error"
+
+	defaultContinueOnError = false
+)
+
+const (
+	perDistro = "distro"
+	perArch   = "arch"
+	perNone   = "none"
+)
+
+func PruneCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+	var searchQuery *packagecloud.Query
+
+	cmd := &cobra.Command{
+		Use:   "prune <user/repo> [query] --keep <count>",
+		Short: "Delete all but the newest versions of each package in a repository",
+		Example: "prune ecorp/nightly --keep 10 --older-than 90d\n" +
+			"prune ecorp/nightly --keep 3 --package ecorp-agent --per distro --protect 1.4.3 --dry-run\n" +
+			"prune ecorp/nightly 'name:ecorp-agent dist:ubuntu/*' --keep 5",
+		Long: `Delete all but the newest versions of each package in a repository.
+
+Only the packages matching the search flags or query (see the search command)
+are pruned. Packages are grouped by name, type, distro version and
+architecture, and the versions of each group are ordered with the version
+scheme of the package type. Every package that is not one of the --keep newest
+versions of its group is deleted, unless its version is protected with
+--protect, it was uploaded less than --older-than ago, or its version can't be
+parsed. Packages of the same version count as a single version, so --per distro
+keeps the newest versions of every architecture of a distro version together,
+--per arch does the same across distro versions, and --per none keeps the
+newest versions across both.
+
+The packages to delete are always listed before anything is deleted (to stderr
+with --format json), and the deletion has to be confirmed unless --yes is set.
+Use --dry-run to only list them. A prune deleting more than --max-deletions
+packages is refused.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) < 1 || len(args) > 2 {
+				return newErrWithUsage("requires 1 or 2 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			if len(args) == 2 {
+				q, err := packagecloud.ParseQuery(args[1], time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			keep, err := cmd.Flags().GetInt(flagKeep)
+			if err != nil {
+				return err
+			}
+
+			per, err := cmd.Flags().GetStringSlice(flagPer)
+			if err != nil {
+				return err
+			}
+
+			olderThan, err := cmd.Flags().GetString(flagOlderThan)
+			if err != nil {
+				return err
+			}
+
+			packageName, err := cmd.Flags().GetString(flagPackage)
+			if err != nil {
+				return err
+			}
+
+			protected, err := cmd.Flags().GetStringSlice(flagProtect)
+			if err != nil {
+				return err
+			}
+
+			maxDeletions, err := cmd.Flags().GetInt(flagMaxDeletions)
+			if err != nil {
+				return err
+			}
+
+			schemeName, err := cmd.Flags().GetString(flagScheme)
+			if err != nil {
+				return err
+			}
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			yes, err := cmd.Flags().GetBool(flagYes)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			if keep < 1 {
+				return newErrWithUsage(fmt.Sprintf("--%s must be at least 1", flagKeep))
+			}
+
+			options := packagecloud.PruneOptions{
+				Repo:        repo,
+				Keep:        keep,
+				PerDistro:   len(per) == 0,
+				PerArch:     len(per) == 0,
+				PackageName: packageName,
+				Filter:      filter,
+				Dist:        dist,
+				Arch:        arch,
+				Protected:   protected,
+				Query:       searchQuery,
+			}
+
+			for _, p := range per {
+				switch p {
+				case perDistro:
+					options.PerDistro = true
+				case perArch:
+					options.PerArch = true
+				case perNone:
+				default:
+					return newErrWithUsage(fmt.Sprintf("invalid --%s %q (must be %s, %s or %s)", flagPer, p, perDistro, perArch, perNone))
+				}
+			}
+
+			if olderThan != "" {
+				options.OlderThan, err = packagecloud.ParseUploadTime(olderThan, time.Now())
+				if err != nil {
+					return newErrWithUsage(fmt.Sprintf("invalid --%s: %s", flagOlderThan, err))
+				}
+			}
+
+			if schemeName != "" {
+				options.Scheme, err = types.GetVersionScheme(schemeName)
+				if err != nil {
+					return newErrWithUsage(err.Error())
+				}
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			if maxDeletions < 1 {
+				return newErrWithUsage(fmt.Sprintf("--%s must be at least 1", flagMaxDeletions))
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			if format == "json" && !yes && !client.DryRun() {
+				return newErrWithUsage("--yes is required when using --format json")
+			}
+
+			plan, err := client.PlanPrune(options)
+			if err != nil {
+				return fmt.Errorf("failed to plan prune: %s", err)
+			}
+
+			// The plan is printed to stderr in json mode, so that it is
+			// always shown before anything is deleted without mixing it
+			// into the results printed to stdout.
+			planOutput := os.Stdout
+			if format == "json" {
+				planOutput = os.Stderr
+			}
+			if err := printPlan(planOutput, format, plan); err != nil {
+				return err
+			}
+
+			if len(plan.Delete) > maxDeletions {
+				return fmt.Errorf("refusing to delete %d package(s), more than the maximum of %d (use --%s to raise it)",
+					len(plan.Delete), maxDeletions, flagMaxDeletions)
+			}
+
+			if len(plan.Delete) > 0 && !yes && !client.DryRun() {
+				question := fmt.Sprintf("Delete %d package(s) from %s?", len(plan.Delete), repo)
+				ok, err := prompt.Confirm(question)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("prune cancelled")
+				}
+			}
+
+			executeOptions := packagecloud.PruneExecuteOptions{
+				MaxDeletions:    maxDeletions,
+				Concurrency:     concurrency,
+				ContinueOnError: continueOnError,
+			}
+
+			results, err := client.ExecutePrune(plan, executeOptions)
+			if err != nil {
+				if len(results) > 0 {
+					printResults(format, client.DryRun(), plan, results)
+				}
+				return fmt.Errorf("failed to prune packages: %s", err)
+			}
+
+			return printResults(format, client.DryRun(), plan, results)
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
+	cmd.Flags().Int(flagKeep, 0, "number of newest versions of each package to keep")
+	cmd.Flags().StringSlice(flagPer, nil, fmt.Sprintf("keep the newest versions per %s and/or %s, or %s to keep them across both (default: %s,%s)",
+		perDistro, perArch, perNone, perDistro, perArch))
+	cmd.Flags().String(flagOlderThan, "", "only delete packages uploaded before this date, timestamp or age (i.e. 2024-01-02, 2024-01-02T15:04:05Z, 90d)")
+	cmd.Flags().String(flagPackage, "", "only prune packages with this name")
+	cmd.Flags().StringSlice(flagProtect, nil, "versions that are never deleted, globs allowed (i.e. 1.4.3, 2.0.*)")
+	cmd.Flags().Int(flagMaxDeletions, defaultMaxDeletions, "maximum number of packages to delete, larger prunes are refused")
+	cmd.Flags().String(flagScheme, "", fmt.Sprintf("version scheme used to order versions - %s (default: chosen from the package type)", strings.Join(types.VersionSchemeNames(), ", ")))
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to prune (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to prune packages of (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to prune packages of (alpine/rpm/debian only)")
+	cmd.Flags().BoolP(flagYes, shortFlagYes, false, "delete the packages without asking for confirmation")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to delete at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep deleting the remaining packages when a deletion fails")
+
+	return cmd
+}
@@ -1,11 +1,14 @@
 package push
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
 	"github.com/amdprophet/packagecloud-go/packagecloud"
@@ -14,9 +17,22 @@ import (
 )
 
 const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "text"
+
 	flagSkipExists = "skip-exists"
 
 	defaultSkipExists = false
+
+	flagWaitForIndexing = "wait-for-indexing"
+
+	defaultWaitForIndexing = false
+
+	flagWaitTimeout = "wait-timeout"
+
+	defaultWaitTimeout = 5 * time.Minute
 )
 
 func PushCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
@@ -52,6 +68,21 @@ func PushCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return fmt.Errorf("failed to parse %s: %s", flagSkipExists, err)
 			}
 
+			waitForIndexing, err := cmd.Flags().GetBool(flagWaitForIndexing)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %s", flagWaitForIndexing, err)
+			}
+
+			waitTimeout, err := cmd.Flags().GetDuration(flagWaitTimeout)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %s", flagWaitTimeout, err)
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
 			filePaths := args[1:]
 
 			packageTypes, err := client.GetDistributions()
@@ -69,12 +100,18 @@ func PushCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return err
 			}
 
+			if client.DryRun() {
+				dstRepo := packagecloud.NewRepo(repo[0], repo[1])
+				distro := packagecloud.NewDistro(repo[2], repo[3])
+				return printPushPlan(client, dstRepo, distro, distroID, filePaths, skipExists, format)
+			}
+
 			for _, filePath := range filePaths {
 				options := packagecloud.PushPackageOptions{
 					RepoUser: repo[0],
 					RepoName: repo[1],
 					DistroID: strconv.Itoa(distroID),
-					FilePath: filePaths[0],
+					FilePath: filePath,
 				}
 
 				fmt.Println("uploading package:", filePath)
@@ -88,11 +125,105 @@ func PushCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				}
 			}
 
+			if waitForIndexing {
+				filenames := make([]string, 0, len(filePaths))
+				for _, filePath := range filePaths {
+					filenames = append(filenames, filepath.Base(filePath))
+				}
+
+				searchOptions := packagecloud.SearchOptions{
+					RepoUser: repo[0],
+					RepoName: repo[1],
+					Dist:     fmt.Sprintf("%s/%s", repo[2], repo[3]),
+				}
+				waitOptions := packagecloud.WaitOptions{
+					Filenames: filenames,
+					Timeout:   waitTimeout,
+					OnPending: func(pending []string, next time.Duration) {
+						fmt.Printf("waiting for %d package(s) to be indexed, checking again in %s\n", len(pending), next)
+					},
+				}
+
+				if _, err := client.WaitForIndexed(cmd.Context(), searchOptions, waitOptions); err != nil {
+					return fmt.Errorf("failed to wait for indexing: %w", err)
+				}
+				fmt.Println("all packages have been indexed")
+			}
+
 			return nil
 		},
 	}
 
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use for the --dry-run plan - text or json")
 	cmd.Flags().Bool(flagSkipExists, defaultSkipExists, "skip over packages that already exist")
+	cmd.Flags().Bool(flagWaitForIndexing, defaultWaitForIndexing, "wait until packagecloud has indexed every uploaded package")
+	cmd.Flags().Duration(flagWaitTimeout, defaultWaitTimeout, "maximum amount of time to wait for uploaded packages to be indexed")
 
 	return cmd
 }
+
+type plannedUpload struct {
+	FilePath   string `json:"file_path"`
+	Repository string `json:"repository"`
+	Distro     string `json:"distro"`
+	DistroID   int    `json:"distro_id"`
+	Exists     bool   `json:"exists"`
+	Action     string `json:"action"`
+}
+
+// printPushPlan resolves what pushing filePaths would do without uploading
+// anything and prints the result.
+func printPushPlan(client *packagecloud.Client, repo packagecloud.Repo, distro packagecloud.Distro, distroID int, filePaths []string, skipExists bool, format string) error {
+	plan := make([]plannedUpload, 0, len(filePaths))
+
+	for _, filePath := range filePaths {
+		if _, err := os.Stat(filePath); err != nil {
+			return fmt.Errorf("failed to stat package: %s", err)
+		}
+
+		existing, err := client.FindPackage(repo, distro, filepath.Base(filePath))
+		if err != nil {
+			return fmt.Errorf("failed to search repository: %s", err)
+		}
+
+		upload := plannedUpload{
+			FilePath:   filePath,
+			Repository: repo.String(),
+			Distro:     distro.String(),
+			DistroID:   distroID,
+			Exists:     existing != nil,
+			Action:     "upload",
+		}
+		if upload.Exists {
+			if skipExists {
+				upload.Action = "skip"
+			} else {
+				upload.Action = "fail"
+			}
+		}
+		plan = append(plan, upload)
+	}
+
+	if format == "json" {
+		bytes, err := json.Marshal(plan)
+		if err != nil {
+			return fmt.Errorf("failed to marshal plan: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	for _, upload := range plan {
+		switch upload.Action {
+		case "skip":
+			fmt.Println("would skip existing package:", upload.FilePath)
+		case "fail":
+			fmt.Println("would fail to upload existing package:", upload.FilePath)
+		default:
+			fmt.Println("would upload package:", upload.FilePath)
+		}
+	}
+	fmt.Printf("dry run: no packages were uploaded to %s (%s, distro id %d)\n", repo, distro, distroID)
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package retarget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/olekukonko/tablewriter"
+)
+
+func printResult(result packagecloud.CopyResult) {
+	switch result.Status {
+	case packagecloud.CopyStatusPlanned:
+		fmt.Printf("would copy %s to %s (%s)\n", result.Package.Filename, result.Destination, result.Distro)
+	case packagecloud.CopyStatusSkipped:
+		fmt.Printf("skipped %s, already exists in %s (%s)\n", result.Package.Filename, result.Destination, result.Distro)
+	case packagecloud.CopyStatusFailed:
+		fmt.Printf("failed to copy %s: %s\n", result.Package.Filename, result.Err)
+	default:
+		fmt.Printf("copied %s to %s (%s)\n", result.Package.Filename, result.Destination, result.Distro)
+	}
+}
+
+func printResults(format string, dryRun bool, results []packagecloud.CopyResult) error {
+	counts := map[packagecloud.CopyStatus]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			Results []packagecloud.CopyResult       `json:"results"`
+			Summary map[packagecloud.CopyStatus]int `json:"summary"`
+		}{
+			Results: results,
+			Summary: counts,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	fmt.Println("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Status", "Name", "Version", "Distro", "Architecture", "Filename", "Error"})
+	table.SetAutoMergeCells(false)
+
+	for _, result := range results {
+		var errMsg string
+		if result.Err != nil {
+			errMsg = result.Err.Error()
+		}
+		table.Append([]string{
+			string(result.Status),
+			result.Package.Name,
+			result.Package.Version,
+			result.Distro,
+			result.Package.Architecture,
+			result.Package.Filename,
+			errMsg,
+		})
+	}
+	table.Render()
+
+	copied := fmt.Sprintf("%d copied", counts[packagecloud.CopyStatusCopied])
+	if dryRun {
+		copied = fmt.Sprintf("%d would be copied", counts[packagecloud.CopyStatusPlanned])
+	}
+	fmt.Printf("\n%s, %d skipped, %d failed\n", copied,
+		counts[packagecloud.CopyStatusSkipped], counts[packagecloud.CopyStatusFailed])
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package retarget
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "text"
+
+	flagFrom = "from"
+
+	flagTo = "to"
+
+	flagQuery      = "query"
+	shortFlagQuery = "q"
+
+	flagArch      = "arch"
+	shortFlagArch = "a"
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+
+	flagContinueOnError = "continue-on-error"
+
+	defaultContinueOnError = false
+)
+
+func RetargetCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+	var searchQuery *packagecloud.Query
+
+	name := "retarget"
+	usage := fmt.Sprintf("%s <%s> [%s] --from <%s> --to <%s>",
+		name,
+		"user/repo",
+		"query",
+		"distro",
+		"distro",
+	)
+	example := fmt.Sprintf("%s %s %s",
+		name,
+		"ecorp/production",
+		"--from ubuntu/jammy --to ubuntu/noble -q ecorp-agent",
+	)
+
+	cmd := &cobra.Command{
+		Use:     usage,
+		Short:   "Re-push packages from one distro version to another within a repository",
+		Example: example,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) < 1 || len(args) > 2 {
+				return newErrWithUsage("requires 1 or 2 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			if len(args) == 2 {
+				q, err := packagecloud.ParseQuery(args[1], time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			fromFlag, err := cmd.Flags().GetString(flagFrom)
+			if err != nil {
+				return err
+			}
+
+			toFlag, err := cmd.Flags().GetString(flagTo)
+			if err != nil {
+				return err
+			}
+
+			from, err := packagecloud.NewDistroFromString(fromFlag)
+			if err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid --%s: %s", flagFrom, err))
+			}
+
+			to, err := packagecloud.NewDistroFromString(toFlag)
+			if err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid --%s: %s", flagTo, err))
+			}
+
+			query, err := cmd.Flags().GetString(flagQuery)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			copyOptions := packagecloud.CopyOptions{
+				Concurrency:     concurrency,
+				ContinueOnError: continueOnError,
+			}
+			if format != "json" {
+				mu := &sync.Mutex{}
+				copyOptions.OnResult = func(result packagecloud.CopyResult) {
+					mu.Lock()
+					defer mu.Unlock()
+					printResult(result)
+				}
+			}
+
+			search := packagecloud.SearchOptions{
+				Query: query,
+				Arch:  arch,
+			}
+			if searchQuery != nil {
+				searchQuery.Apply(&search)
+			}
+
+			results, err := client.Retarget(repo, from, to, search, copyOptions)
+			if len(results) > 0 {
+				if err := printResults(format, client.DryRun(), results); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to retarget packages: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
+	cmd.Flags().String(flagFrom, "", "distro to copy packages from (i.e. ubuntu/jammy)")
+	cmd.Flags().String(flagTo, "", "distro to push packages to (i.e. ubuntu/noble)")
+	cmd.Flags().StringP(flagQuery, shortFlagQuery, "", "search string to search for package filename(s)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to retarget at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep retargeting the remaining packages when one fails")
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/amdprophet/packagecloud-go/command/output"
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+var (
+	defaultColumns = []string{"name", "distro_version", "version", "release", "epoch", "indexed", "filename", "type"}
+
+	defaultSort = []string{"name", "version", "distro_version", "architecture"}
+)
+
+// packageRow is a row of search results.
+type packageRow struct {
+	// Repository is the repository the package is in (user/repo).
+	Repository string `json:"repository"`
+
+	types.PackageFragment
+}
+
+func packageColumns() output.Columns {
+	columns := output.StructColumns(packageRow{})
+	for i := range columns {
+		switch columns[i].Name {
+		case "repository":
+			columns[i].Aliases = []string{"repo"}
+		case "distro_version":
+			columns[i].Header = "Distro"
+			columns[i].Aliases = []string{"distro"}
+		case "version":
+			columns[i].Compare = comparePackageVersions
+		case "architecture":
+			columns[i].Aliases = []string{"arch"}
+		case "created_at":
+			columns[i].Aliases = []string{"created", "uploaded"}
+			columns[i].Compare = comparePackageCreatedAt
+		case "uploader_name":
+			columns[i].Aliases = []string{"uploader"}
+		case "total_downloads_count":
+			columns[i].Aliases = []string{"downloads"}
+		}
+	}
+	return columns
+}
+
+// comparePackageVersions compares the version keys of two packages with the
+// version scheme of their package type.
+func comparePackageVersions(a, b interface{}) int {
+	pkgA := a.(packageRow)
+	pkgB := b.(packageRow)
+	schemeA := types.VersionSchemeForPackageType(pkgA.Type)
+	schemeB := types.VersionSchemeForPackageType(pkgB.Type)
+	versionA := types.PackageVersionKey(schemeA, pkgA.Epoch, pkgA.Version, pkgA.Release)
+	versionB := types.PackageVersionKey(schemeB, pkgB.Epoch, pkgB.Version, pkgB.Release)
+
+	if pkgA.Type != pkgB.Type {
+		return strings.Compare(versionA, versionB)
+	}
+	return types.CompareVersionsLenient(schemeA, versionA, versionB)
+}
+
+// comparePackageCreatedAt compares the upload time of two packages. Invalid
+// upload times sort first.
+func comparePackageCreatedAt(a, b interface{}) int {
+	createdA, _ := a.(packageRow).CreatedTime()
+	createdB, _ := b.(packageRow).CreatedTime()
+	return createdA.Compare(createdB)
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagName = "name"
+
+	flagNameRegex = "name-regex"
+
+	flagVersion = "version"
+
+	flagUploadedAfter = "uploaded-after"
+
+	flagUploadedBefore = "uploaded-before"
+
+	flagUploader = "uploader"
+
+	flagIndexed = "indexed"
+
+	flagPrivate = "private"
+)
+
+// AddFilterFlags adds the flags of the client-side filters returned by
+// GetFilters to cmd.
+func AddFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagName, "", "only show packages with exactly this name")
+	cmd.Flags().String(flagNameRegex, "", "only show packages with a name matching this regular expression")
+	cmd.Flags().String(flagVersion, "", "only show packages with a version matching this constraint (i.e. '>=1.4, <2', '~3.2')")
+	cmd.Flags().String(flagUploadedAfter, "", "only show packages uploaded after this date, timestamp or age (i.e. 2024-01-02, 2024-01-02T15:04:05Z, 30d)")
+	cmd.Flags().String(flagUploadedBefore, "", "only show packages uploaded before this date, timestamp or age (i.e. 2024-01-02, 2024-01-02T15:04:05Z, 30d)")
+	cmd.Flags().String(flagUploader, "", "only show packages uploaded by this user")
+	cmd.Flags().String(flagIndexed, "", "only show packages that have (true) or have not (false) been indexed")
+	cmd.Flags().String(flagPrivate, "", "only show packages that are (true) or are not (false) private")
+}
+
+// GetFilters returns the client-side filters set with the command's filter
+// flags (see AddFilterFlags).
+func GetFilters(cmd *cobra.Command, now time.Time) ([]packagecloud.FragmentFilter, error) {
+	var filters []packagecloud.FragmentFilter
+
+	name, err := cmd.Flags().GetString(flagName)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		filters = append(filters, packagecloud.NameFilter(name))
+	}
+
+	nameRegex, err := cmd.Flags().GetString(flagNameRegex)
+	if err != nil {
+		return nil, err
+	}
+	if nameRegex != "" {
+		re, err := regexp.Compile(nameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name regex: %w", err)
+		}
+		filters = append(filters, packagecloud.NameRegexpFilter(re))
+	}
+
+	version, err := cmd.Flags().GetString(flagVersion)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		constraint, err := types.ParseVersionConstraint(version)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, packagecloud.VersionFilter(constraint, nil))
+	}
+
+	uploadedAfter, err := cmd.Flags().GetString(flagUploadedAfter)
+	if err != nil {
+		return nil, err
+	}
+	if uploadedAfter != "" {
+		t, err := packagecloud.ParseUploadTime(uploadedAfter, now)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, packagecloud.UploadedAfterFilter(t))
+	}
+
+	uploadedBefore, err := cmd.Flags().GetString(flagUploadedBefore)
+	if err != nil {
+		return nil, err
+	}
+	if uploadedBefore != "" {
+		t, err := packagecloud.ParseUploadTime(uploadedBefore, now)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, packagecloud.UploadedBeforeFilter(t))
+	}
+
+	uploader, err := cmd.Flags().GetString(flagUploader)
+	if err != nil {
+		return nil, err
+	}
+	if uploader != "" {
+		filters = append(filters, packagecloud.UploaderFilter(uploader))
+	}
+
+	indexed, err := cmd.Flags().GetString(flagIndexed)
+	if err != nil {
+		return nil, err
+	}
+	if indexed != "" {
+		b, err := strconv.ParseBool(indexed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --%s: %q (must be true or false)", flagIndexed, indexed)
+		}
+		filters = append(filters, packagecloud.IndexedFilter(b))
+	}
+
+	private, err := cmd.Flags().GetString(flagPrivate)
+	if err != nil {
+		return nil, err
+	}
+	if private != "" {
+		b, err := strconv.ParseBool(private)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --%s: %q (must be true or false)", flagPrivate, private)
+		}
+		filters = append(filters, packagecloud.PrivateFilter(b))
+	}
+
+	return filters, nil
+}
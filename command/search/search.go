@@ -1,16 +1,16 @@
 package search
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/output"
 	"github.com/amdprophet/packagecloud-go/packagecloud"
-	"github.com/olekukonko/tablewriter"
+	"github.com/amdprophet/packagecloud-go/types"
 	"github.com/spf13/cobra"
 )
 
@@ -38,30 +38,77 @@ const (
 	flagWaitForIndexing      = "wait-for-indexing"
 	shortFlagWaitForIndexing = "w"
 
+	flagWaitInterval = "wait-interval"
+
+	flagWaitTimeout = "wait-timeout"
+
 	flagWaitSeconds      = "wait-seconds"
 	shortFlagWaitSeconds = "s"
 
 	flagWaitMaxRetries      = "wait-max-retries"
 	shortFlagWaitMaxRetries = "r"
+
+	flagRepoConcurrency = "repo-concurrency"
+
+	defaultRepoConcurrency = 4
 )
 
 func SearchCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
-	var repo []string
+	var repoPatterns []string
+	var multipleRepos bool
+	var searchQuery *packagecloud.Query
+	columns := packageColumns()
 
 	cmd := &cobra.Command{
-		Use:   "search user/repo",
+		Use:   "search user/repo [user/repo | user/* ...] [query]",
 		Short: "Search for packages matching given search parameters",
+		Example: "search ecorp/production 'name:ecorp-agent version:>=1.4 arch:amd64 dist:ubuntu/* uploaded:<30d'\n" +
+			"search 'ecorp/*' ecorp-agent_1.4.3",
+		Long: `Search for packages matching given search parameters.
+
+Several repositories can be searched at once by passing more than one
+user/repo, or a pattern such as user/* or user/prod-* that is expanded through
+the list of repositories the token has access to. Results are merged and
+include the repository of each package. Repositories that can't be searched
+are reported without failing the whole search.
+
+The optional query is a list of space separated terms, all of which must match.
+Each term is either a field:value pair or text the package filename must
+contain. Quote values containing spaces and negate a term with a leading -.
+
+  name:ecorp-agent       exact name, glob (ecorp-*) or regular expression (/^ecorp-/)
+  filename:1.4.3         text the filename must contain
+  version:>=1.4          version constraint (i.e. ~3.2, ">=1.4, <2")
+  arch:amd64             architecture, glob allowed (i.e. arm*)
+  dist:ubuntu/*          distro or distro version, glob allowed (i.e. el, el/9)
+  type:deb               package type
+  uploaded:<30d          uploaded less than 30 days ago (or uploaded:>2024-01-02)
+  uploader:alice         name of the uploader
+  indexed:false          indexing status
+  private:true           whether or not the package is private`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
 
-			if len(args) != 1 {
-				return newErrWithUsage("requires exactly 1 argument")
+			if len(args) < 1 {
+				return newErrWithUsage("requires at least 1 argument")
+			}
+
+			repoPatterns = args
+			if last := args[len(args)-1]; len(args) > 1 && !packagecloud.IsRepoPattern(last) {
+				q, err := packagecloud.ParseQuery(last, time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+				repoPatterns = args[:len(args)-1]
 			}
 
-			repo = strings.Split(args[0], "/")
-			if len(repo) != 2 {
-				return newErrWithUsage("invalid repo, use format user/repo")
+			for _, pattern := range repoPatterns {
+				if !packagecloud.IsRepoPattern(pattern) {
+					return newErrWithUsage(fmt.Sprintf("invalid repo %q, use format user/repo or user/*", pattern))
+				}
 			}
+			multipleRepos = len(repoPatterns) > 1 || strings.ContainsAny(repoPatterns[0], `*?[\`)
 
 			return nil
 		},
@@ -98,6 +145,16 @@ func SearchCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return err
 			}
 
+			waitInterval, err := cmd.Flags().GetDuration(flagWaitInterval)
+			if err != nil {
+				return err
+			}
+
+			waitTimeout, err := cmd.Flags().GetDuration(flagWaitTimeout)
+			if err != nil {
+				return err
+			}
+
 			waitSeconds, err := cmd.Flags().GetInt(flagWaitSeconds)
 			if err != nil {
 				return err
@@ -108,104 +165,182 @@ func SearchCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return err
 			}
 
-			options := packagecloud.SearchOptions{
-				RepoUser: repo[0],
-				RepoName: repo[1],
-				Query:    query,
-				Filter:   filter,
-				Dist:     dist,
-				Arch:     arch,
-				PerPage:  perPage,
+			// the deprecated retry flags take precedence when they are set
+			if waitSeconds > 0 {
+				waitInterval = time.Duration(waitSeconds) * time.Second
+			}
+			if waitMaxRetries > 0 {
+				waitTimeout = time.Duration(waitMaxRetries) * waitInterval
 			}
 
-			if err := options.Validate(); err != nil {
+			filters, err := GetFilters(cmd, time.Now())
+			if err != nil {
 				return newErrWithUsage(err.Error())
 			}
 
-			client, err := getClientFn()
+			repoConcurrency, err := cmd.Flags().GetInt(flagRepoConcurrency)
 			if err != nil {
 				return err
 			}
 
+			if multipleRepos && waitForIndexing {
+				return newErrWithUsage(fmt.Sprintf("--%s can only be used when searching a single repository", flagWaitForIndexing))
+			}
+
+			options := packagecloud.SearchOptions{
+				Query:   query,
+				Filter:  filter,
+				Dist:    dist,
+				Arch:    arch,
+				PerPage: perPage,
+				Filters: filters,
+			}
+			if searchQuery != nil {
+				searchQuery.Apply(&options)
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
 			format, err := cmd.Flags().GetString(flagFormat)
 			if err != nil {
 				return fmt.Errorf("failed to parse format: %s", err)
 			}
 
-			waitRetries := 0
-			for {
-				packages, err := client.Search(options)
-				if err != nil {
-					return fmt.Errorf("failed to retrieve search results: %s", err)
-				}
+			printColumns := defaultColumns
+			printSort := defaultSort
+			if multipleRepos {
+				printColumns = append([]string{"repository"}, defaultColumns...)
+				printSort = append(append([]string{}, defaultSort...), "repository")
+			}
 
-				indexed := packages.Indexed()
+			printer, err := output.NewPrinterFromFlags(cmd, format, columns, printColumns, printSort)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
 
-				if waitForIndexing && !indexed {
-					if waitRetries >= waitMaxRetries {
-						// packages have not been indexed after max retries has been reached
-						return fmt.Errorf("packages have not finished indexing after %d seconds", waitSeconds*waitMaxRetries)
-					}
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
 
-					if format != "json" {
-						fmt.Println("\nOne or more packages have not yet been indexed")
-						fmt.Printf("Waiting %d seconds before trying again\n", waitSeconds)
-						fmt.Println("")
-					}
+			repos, err := client.ExpandRepos(repoPatterns)
+			if err != nil {
+				return err
+			}
 
-					for i := 0; i < waitSeconds; i++ {
-						if format != "json" {
-							fmt.Printf(".")
-						}
-						time.Sleep(1 * time.Second)
-					}
+			if multipleRepos {
+				return searchRepos(client, repos, options, repoConcurrency, printer)
+			}
 
-					waitRetries++
-					continue
-				}
+			repo := repos[0]
+			options.RepoUser = repo.User
+			options.RepoName = repo.Name
+
+			// Print packages as each page arrives unless all of them are
+			// needed first.
+			if printer.Streaming() && !waitForIndexing {
+				return streamPackages(client, repo, options, printer)
+			}
+
+			packages, err := client.Search(options)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve search results: %s", err)
+			}
 
-				if format == "json" {
-					bytes, err := json.Marshal(packages)
-					if err != nil {
-						return fmt.Errorf("failed to marshal packages: %w", err)
+			if waitForIndexing && !packages.Indexed() {
+				waitOptions := packagecloud.WaitOptions{
+					InitialInterval: waitInterval,
+					Timeout:         waitTimeout,
+				}
+				if format == output.FormatTable {
+					waitOptions.OnPending = func(pending []string, next time.Duration) {
+						fmt.Printf("%d package(s) have not yet been indexed, checking again in %s\n", len(pending), next)
 					}
-					fmt.Println(string(bytes))
-					return nil
 				}
 
-				table := tablewriter.NewWriter(os.Stdout)
-				table.SetHeader([]string{"Name", "Distro", "Version", "Release", "Epoch", "Indexed", "Filename", "Type"})
-				table.SetAutoMergeCells(false)
-
-				for _, pkg := range packages {
-					row := []string{
-						pkg.Name,
-						pkg.DistroVersion,
-						pkg.Version,
-						pkg.Release,
-						strconv.Itoa(pkg.Epoch),
-						strconv.FormatBool(pkg.Indexed),
-						pkg.Filename,
-						pkg.Type,
-					}
-					table.Append(row)
+				packages, err = client.WaitForIndexed(cmd.Context(), options, waitOptions)
+				if err != nil {
+					return err
 				}
-				table.Render()
+			}
 
-				return nil
+			rows := make([]interface{}, 0, len(packages))
+			for _, pkg := range packages {
+				rows = append(rows, packageRow{Repository: repo.String(), PackageFragment: pkg})
 			}
+
+			return printer.Print(os.Stdout, rows)
 		},
 	}
 
-	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - table or json")
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, output.FormatUsage)
 	cmd.Flags().StringP(flagQuery, shortFlagQuery, "", "search string to search for package filename(s)")
 	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
 	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
 	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
 	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
 	cmd.Flags().BoolP(flagWaitForIndexing, shortFlagWaitForIndexing, false, "wait for packages matching the search string to be indexed")
-	cmd.Flags().IntP(flagWaitSeconds, shortFlagWaitSeconds, 10, "seconds to wait for retrying to check if packages have been indexed")
-	cmd.Flags().IntP(flagWaitMaxRetries, shortFlagWaitMaxRetries, 12, "maximum amount of retry attempts to check if packages have been indexed")
+	cmd.Flags().Duration(flagWaitInterval, 2*time.Second, "initial delay between checks for indexing, doubled after each check")
+	cmd.Flags().Duration(flagWaitTimeout, 2*time.Minute, "maximum amount of time to wait for packages to be indexed")
+	cmd.Flags().IntP(flagWaitSeconds, shortFlagWaitSeconds, 0, "seconds to wait for retrying to check if packages have been indexed")
+	cmd.Flags().IntP(flagWaitMaxRetries, shortFlagWaitMaxRetries, 0, "maximum amount of retry attempts to check if packages have been indexed")
+	cmd.Flags().MarkDeprecated(flagWaitSeconds, "use --wait-interval instead")
+	cmd.Flags().MarkDeprecated(flagWaitMaxRetries, "use --wait-timeout instead")
+	cmd.Flags().Int(flagRepoConcurrency, defaultRepoConcurrency, "maximum number of repositories to search at the same time")
+	AddFilterFlags(cmd)
+	output.AddFlags(cmd, columns)
 
 	return cmd
 }
+
+// streamPackages prints the packages matching options as each page of
+// results arrives.
+func streamPackages(client *packagecloud.Client, repo packagecloud.Repo, options packagecloud.SearchOptions, printer *output.Printer) error {
+	var printErr error
+	mu := &sync.Mutex{}
+
+	err := client.SearchStream(options, func(packages types.PackageFragments) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, pkg := range packages {
+			if printErr != nil {
+				return
+			}
+			printErr = printer.PrintRow(os.Stdout, packageRow{Repository: repo.String(), PackageFragment: pkg})
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve search results: %s", err)
+	}
+
+	return printErr
+}
+
+// searchRepos searches repos concurrently and prints the merged results.
+// Repositories that can't be searched are reported on stderr, and an error
+// is only returned if none of them could be searched.
+func searchRepos(client *packagecloud.Client, repos []packagecloud.Repo, options packagecloud.SearchOptions, concurrency int, printer *output.Printer) error {
+	results := client.SearchRepos(repos, options, concurrency)
+
+	var rows []interface{}
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to search %s: %s\n", result.Repo, result.Err)
+			failed = append(failed, result.Repo.String())
+			continue
+		}
+		for _, pkg := range result.Packages {
+			rows = append(rows, packageRow{Repository: result.Repo.String(), PackageFragment: pkg})
+		}
+	}
+
+	if len(failed) == len(results) {
+		return fmt.Errorf("failed to search all %d repositories: %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	return printer.Print(os.Stdout, rows)
+}
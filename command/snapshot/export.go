@@ -0,0 +1,108 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagOutput      = "output"
+	shortFlagOutput = "o"
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+)
+
+func ExportCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+
+	cmd := &cobra.Command{
+		Use:     "export <user/repo>",
+		Short:   "Write a snapshot of every package in a repository",
+		Example: "snapshot export ecorp/production > production-v2.3.0.json",
+		Long: `Write a snapshot of every package in a repository.
+
+The snapshot is a JSON file recording the name, type, version, distro version,
+architecture, filename and checksum of every package, which can later be
+compared with a repository using snapshot verify, or used to bring back the
+packages missing from a repository using snapshot restore. Recording the
+checksums retrieves the details of every package.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 1 {
+				return newErrWithUsage("requires 1 argument")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputPath, err := cmd.Flags().GetString(flagOutput)
+			if err != nil {
+				return err
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			snapshot, err := client.ExportSnapshot(repo, packagecloud.SnapshotOptions{
+				Concurrency: concurrency,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to export snapshot: %s", err)
+			}
+
+			if outputPath == "" {
+				return snapshot.Write(os.Stdout)
+			}
+
+			file, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create snapshot file: %s", err)
+			}
+			if err := snapshot.Write(file); err != nil {
+				file.Close()
+				return fmt.Errorf("failed to write snapshot file: %s", err)
+			}
+			return file.Close()
+		},
+	}
+
+	cmd.Flags().StringP(flagOutput, shortFlagOutput, "", "file to write the snapshot to instead of stdout")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to retrieve the details of at the same time")
+
+	return cmd
+}
+
+// readSnapshot reads a snapshot from a file, or from stdin if path is "-".
+func readSnapshot(path string) (*packagecloud.Snapshot, error) {
+	if path == "-" {
+		return packagecloud.ReadSnapshot(os.Stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	return packagecloud.ReadSnapshot(file)
+}
@@ -0,0 +1,19 @@
+package snapshot
+
+import (
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/spf13/cobra"
+)
+
+func HelpCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Record the packages of a repository and verify or restore them later",
+	}
+
+	cmd.AddCommand(ExportCommand(getClientFn))
+	cmd.AddCommand(VerifyCommand(getClientFn))
+	cmd.AddCommand(RestoreCommand(getClientFn))
+
+	return cmd
+}
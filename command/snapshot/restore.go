@@ -0,0 +1,190 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultRestoreFormat = "text"
+
+	flagPush = "push"
+
+	defaultPush = false
+
+	flagContinueOnError = "continue-on-error"
+
+	defaultContinueOnError = false
+)
+
+func RestoreCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var srcRepo, dstRepo packagecloud.Repo
+	var snapshotPath string
+
+	cmd := &cobra.Command{
+		Use:   "restore <source user/repo> <destination user/repo> <snapshot file>",
+		Short: "Restore the packages of a snapshot that are missing from a repository",
+		Example: "snapshot restore ecorp/staging ecorp/production production-v2.3.0.json\n" +
+			"snapshot restore ecorp/archive ecorp/production production-v2.3.0.json --push",
+		Long: `Restore the packages of a snapshot that are missing from a repository.
+
+Every package recorded in the snapshot that does not exist in the destination
+repository is looked up by distro version and filename in the source
+repository, and promoted from it. With --push, the package is instead
+downloaded from the source repository and pushed to the destination, which
+leaves the source repository untouched.
+
+A package is only restored if its checksum in the source repository matches
+the one recorded in the snapshot. Packages that exist in the destination
+repository are left as they are, use snapshot verify to find the ones that
+have changed. Use - as the snapshot file to read it from stdin.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 3 {
+				return newErrWithUsage("requires 3 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid source repo: %s", err))
+			} else {
+				srcRepo = arg
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[1]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid destination repo: %s", err))
+			} else {
+				dstRepo = arg
+			}
+
+			if srcRepo == dstRepo {
+				return newErrWithUsage("the source and destination repositories must be different")
+			}
+
+			snapshotPath = args[2]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			push, err := cmd.Flags().GetBool(flagPush)
+			if err != nil {
+				return err
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			snapshot, err := readSnapshot(snapshotPath)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			options := packagecloud.RestoreOptions{
+				Method:          packagecloud.RestoreMethodPromote,
+				Concurrency:     concurrency,
+				ContinueOnError: continueOnError,
+			}
+			if push {
+				options.Method = packagecloud.RestoreMethodPush
+			}
+
+			results, err := client.RestoreSnapshot(snapshot, srcRepo, dstRepo, options)
+			if len(results) > 0 || err == nil {
+				if err := printRestoreResults(format, client.DryRun(), results); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restore snapshot: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultRestoreFormat, "output format to use - text or json")
+	cmd.Flags().Bool(flagPush, defaultPush, "download packages from the source repository and push them instead of promoting them")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to restore at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep restoring the remaining packages when a restore fails")
+
+	return cmd
+}
+
+// printRestoreResults prints the outcome of restoring each package in the
+// given format.
+func printRestoreResults(format string, dryRun bool, results []packagecloud.RestoreResult) error {
+	counts := map[packagecloud.RestoreStatus]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			Results []packagecloud.RestoreResult       `json:"results"`
+			Summary map[packagecloud.RestoreStatus]int `json:"summary"`
+		}{
+			Results: results,
+			Summary: counts,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	if len(results) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Status", "Name", "Version", "Distro", "Architecture", "Filename", "Error"})
+		table.SetAutoMergeCells(false)
+
+		for _, result := range results {
+			var errMsg string
+			if result.Err != nil {
+				errMsg = result.Err.Error()
+			}
+			table.Append([]string{
+				string(result.Status),
+				result.Package.Name,
+				result.Package.Version,
+				result.Package.DistroVersion,
+				result.Package.Architecture,
+				result.Package.Filename,
+				errMsg,
+			})
+		}
+		table.Render()
+		fmt.Println("")
+	}
+
+	restored := fmt.Sprintf("%d restored", counts[packagecloud.RestoreStatusRestored])
+	if dryRun {
+		restored = fmt.Sprintf("%d would be restored", counts[packagecloud.RestoreStatusPlanned])
+	}
+	fmt.Printf("%s, %d skipped, %d failed\n", restored, counts[packagecloud.RestoreStatusSkipped], counts[packagecloud.RestoreStatusFailed])
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/output"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "table"
+
+	flagSkipChecksums = "skip-checksums"
+
+	defaultSkipChecksums = false
+)
+
+var defaultColumns = []string{"status", "name", "version", "distro_version", "architecture", "filename"}
+
+func driftColumns() output.Columns {
+	columns := output.StructColumns(packagecloud.DriftEntry{})
+	for i := range columns {
+		switch columns[i].Name {
+		case "version":
+			columns[i].Compare = compareEntryVersions
+		case "distro_version":
+			columns[i].Header = "Distro"
+			columns[i].Aliases = []string{"distro"}
+		case "architecture":
+			columns[i].Aliases = []string{"arch"}
+		case "checksum":
+			columns[i].Header = "Expected Checksum"
+		}
+	}
+	return columns
+}
+
+// compareEntryVersions compares the versions of two entries with the
+// version scheme of their package type.
+func compareEntryVersions(a, b interface{}) int {
+	entryA := a.(packagecloud.DriftEntry)
+	entryB := b.(packagecloud.DriftEntry)
+
+	if entryA.Type != entryB.Type {
+		return strings.Compare(entryA.Version, entryB.Version)
+	}
+	return types.CompareVersionsLenient(types.VersionSchemeForPackageType(entryA.Type), entryA.Version, entryB.Version)
+}
+
+func VerifyCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+	var snapshotPath string
+	columns := driftColumns()
+
+	cmd := &cobra.Command{
+		Use:   "verify <user/repo> <snapshot file>",
+		Short: "Report how a repository has drifted from a snapshot",
+		Example: "snapshot verify ecorp/production production-v2.3.0.json\n" +
+			"snapshot verify ecorp/production production-v2.3.0.json -f json",
+		Long: `Report how a repository has drifted from a snapshot.
+
+Packages are matched by distro version and filename. A package is reported as
+missing when it is in the snapshot but not in the repository, as extra when it
+is in the repository but not in the snapshot, and as changed when its checksum
+differs from the one recorded in the snapshot. Comparing checksums retrieves
+the details of every package of the snapshot that exists in the repository,
+use --skip-checksums to only compare which packages exist.
+
+The command exits with an error if the repository has drifted. Use - as the
+snapshot file to read it from stdin.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 2 {
+				return newErrWithUsage("requires 2 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			snapshotPath = args[1]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			skipChecksums, err := cmd.Flags().GetBool(flagSkipChecksums)
+			if err != nil {
+				return err
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			printer, err := output.NewPrinterFromFlags(cmd, format, columns, defaultColumns, nil)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			snapshot, err := readSnapshot(snapshotPath)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			drift, err := client.VerifySnapshot(repo, snapshot, packagecloud.VerifySnapshotOptions{
+				SkipChecksums: skipChecksums,
+				Concurrency:   concurrency,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to verify snapshot: %s", err)
+			}
+
+			rows := make([]interface{}, 0, len(drift.Entries))
+			for _, entry := range drift.Entries {
+				rows = append(rows, entry)
+			}
+
+			if format == output.FormatTable {
+				if len(rows) > 0 {
+					if err := printer.Print(os.Stdout, rows); err != nil {
+						return err
+					}
+					fmt.Println("")
+				}
+				printSummary(drift)
+			} else if err := printer.Print(os.Stdout, rows); err != nil {
+				return err
+			}
+
+			if len(drift.Entries) > 0 {
+				return fmt.Errorf("%s has drifted from the snapshot of %s taken at %s",
+					repo, snapshot.Repository, snapshot.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, output.FormatUsage)
+	cmd.Flags().Bool(flagSkipChecksums, defaultSkipChecksums, "don't compare the checksums of packages that exist in the repository")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to compare the checksums of at the same time")
+	output.AddFlags(cmd, columns)
+
+	return cmd
+}
+
+func printSummary(drift *packagecloud.SnapshotDrift) {
+	counts := map[packagecloud.DriftStatus]int{}
+	for _, entry := range drift.Entries {
+		counts[entry.Status]++
+	}
+
+	fmt.Printf("%d missing, %d extra, %d changed, %d identical\n",
+		counts[packagecloud.DriftStatusMissing],
+		counts[packagecloud.DriftStatusExtra],
+		counts[packagecloud.DriftStatusChanged],
+		drift.Same)
+}
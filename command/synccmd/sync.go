@@ -0,0 +1,314 @@
+package synccmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/prompt"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFormat      = "format"
+	shortFlagFormat = "f"
+
+	defaultFormat = "text"
+
+	flagQuery      = "query"
+	shortFlagQuery = "q"
+
+	flagFilter      = "filter"
+	shortFlagFilter = "i"
+
+	flagDist      = "dist"
+	shortFlagDist = "d"
+
+	flagArch      = "arch"
+	shortFlagArch = "a"
+
+	flagDeleteExtraneous = "delete-extraneous"
+
+	defaultDeleteExtraneous = false
+
+	flagYes      = "yes"
+	shortFlagYes = "y"
+
+	flagConcurrency = "concurrency"
+
+	defaultConcurrency = 1
+
+	flagContinueOnError = "continue-on-error"
+
+	defaultContinueOnError = false
+)
+
+func SyncCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var srcRepo, dstRepo packagecloud.Repo
+	var searchQuery *packagecloud.Query
+
+	cmd := &cobra.Command{
+		Use:   "sync <source repository> <destination repository> [query]",
+		Short: "Promote the packages of a repository that are missing from another",
+		Example: "sync ecorp/staging ecorp/qa -i Debs\n" +
+			"sync ecorp/staging ecorp/qa 'name:ecorp-agent dist:ubuntu/*' --delete-extraneous",
+		Long: `Promote the packages of a repository that are missing from another.
+
+Every package of the source repository matching the search flags or query (see
+the search command) that does not exist in the destination repository is
+promoted to it, so running the same sync again changes nothing. Without search
+flags or a query, every package of the source repository is synced. Packages
+are matched by distro version and filename, and packages that exist in both
+repositories are left as they are.
+
+With --delete-extraneous, the packages of the destination repository matching
+the same search that don't exist in the source repository are deleted, after
+they have been listed and the deletion has been confirmed (unless --yes is
+set). Use --dry-run to see what would change.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) < 2 || len(args) > 3 {
+				return newErrWithUsage("requires 2 or 3 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid source repo: %s", err))
+			} else {
+				srcRepo = arg
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[1]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid destination repo: %s", err))
+			} else {
+				dstRepo = arg
+			}
+
+			if srcRepo == dstRepo {
+				return newErrWithUsage("the source and destination repositories must be different")
+			}
+
+			if len(args) == 3 {
+				q, err := packagecloud.ParseQuery(args[2], time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			query, err := cmd.Flags().GetString(flagQuery)
+			if err != nil {
+				return err
+			}
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			deleteExtraneous, err := cmd.Flags().GetBool(flagDeleteExtraneous)
+			if err != nil {
+				return err
+			}
+
+			yes, err := cmd.Flags().GetBool(flagYes)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			continueOnError, err := cmd.Flags().GetBool(flagContinueOnError)
+			if err != nil {
+				return err
+			}
+
+			options := packagecloud.SearchOptions{
+				Query:  query,
+				Filter: filter,
+				Dist:   dist,
+				Arch:   arch,
+			}
+			if searchQuery != nil {
+				searchQuery.Apply(&options)
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			if format == "json" && deleteExtraneous && !yes && !client.DryRun() {
+				return newErrWithUsage(fmt.Sprintf("--yes is required when using --format json with --%s", flagDeleteExtraneous))
+			}
+
+			plan, err := client.PlanSync(srcRepo, dstRepo, options, deleteExtraneous)
+			if err != nil {
+				return fmt.Errorf("failed to compare repositories: %s", err)
+			}
+
+			if len(plan.Delete) > 0 && !yes && !client.DryRun() {
+				if format != "json" {
+					printDeletions(plan.Delete)
+				}
+
+				question := fmt.Sprintf("Delete %d package(s) from %s that are not in %s?", len(plan.Delete), dstRepo, srcRepo)
+				ok, err := prompt.Confirm(question)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("sync cancelled")
+				}
+			}
+
+			syncOptions := packagecloud.SyncOptions{
+				Concurrency:     concurrency,
+				ContinueOnError: continueOnError,
+			}
+
+			results, err := client.ExecuteSync(plan, syncOptions)
+			if len(results) > 0 || err == nil {
+				if err := printResults(format, client.DryRun(), plan, results); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to sync packages: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - text or json")
+	cmd.Flags().StringP(flagQuery, shortFlagQuery, "", "search string to search for package filename(s)")
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().Bool(flagDeleteExtraneous, defaultDeleteExtraneous, "delete the packages of the destination repository that are not in the source repository")
+	cmd.Flags().BoolP(flagYes, shortFlagYes, false, "delete extraneous packages without asking for confirmation")
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "maximum number of packages to promote or delete at the same time")
+	cmd.Flags().Bool(flagContinueOnError, defaultContinueOnError, "keep syncing the remaining packages when a promotion or deletion fails")
+
+	return cmd
+}
+
+// printDeletions prints a table of the packages that are about to be
+// deleted.
+func printDeletions(packages types.PackageFragments) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Distro", "Version", "Release", "Epoch", "Architecture", "Filename"})
+	table.SetAutoMergeCells(false)
+
+	for _, pkg := range packages {
+		table.Append([]string{
+			pkg.Name,
+			pkg.DistroVersion,
+			pkg.Version,
+			pkg.Release,
+			strconv.Itoa(pkg.Epoch),
+			pkg.Architecture,
+			pkg.Filename,
+		})
+	}
+	table.Render()
+	fmt.Println("")
+}
+
+// printResults prints a report of what a sync changed in the given format.
+func printResults(format string, dryRun bool, plan *packagecloud.SyncPlan, results []packagecloud.SyncResult) error {
+	counts := map[packagecloud.SyncAction]map[packagecloud.SyncStatus]int{
+		packagecloud.SyncActionPromote: {},
+		packagecloud.SyncActionDelete:  {},
+	}
+	for _, result := range results {
+		counts[result.Action][result.Status]++
+	}
+
+	if format == "json" {
+		bytes, err := json.Marshal(struct {
+			Results []packagecloud.SyncResult                                   `json:"results"`
+			Summary map[packagecloud.SyncAction]map[packagecloud.SyncStatus]int `json:"summary"`
+			InSync  int                                                         `json:"in_sync"`
+		}{
+			Results: results,
+			Summary: counts,
+			InSync:  plan.InSync,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	if len(results) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Action", "Status", "Name", "Version", "Distro", "Architecture", "Filename", "Error"})
+		table.SetAutoMergeCells(false)
+
+		for _, result := range results {
+			var errMsg string
+			if result.Err != nil {
+				errMsg = result.Err.Error()
+			}
+			table.Append([]string{
+				string(result.Action),
+				string(result.Status),
+				result.Package.Name,
+				result.Package.Version,
+				result.Package.DistroVersion,
+				result.Package.Architecture,
+				result.Package.Filename,
+				errMsg,
+			})
+		}
+		table.Render()
+		fmt.Println("")
+	}
+
+	promoted := fmt.Sprintf("%d promoted", counts[packagecloud.SyncActionPromote][packagecloud.SyncStatusPromoted])
+	deleted := fmt.Sprintf("%d deleted", counts[packagecloud.SyncActionDelete][packagecloud.SyncStatusDeleted])
+	if dryRun {
+		promoted = fmt.Sprintf("%d would be promoted", counts[packagecloud.SyncActionPromote][packagecloud.SyncStatusPlanned])
+		deleted = fmt.Sprintf("%d would be deleted", counts[packagecloud.SyncActionDelete][packagecloud.SyncStatusPlanned])
+	}
+	exists := counts[packagecloud.SyncActionPromote][packagecloud.SyncStatusExists]
+	skipped := counts[packagecloud.SyncActionPromote][packagecloud.SyncStatusSkipped] + counts[packagecloud.SyncActionDelete][packagecloud.SyncStatusSkipped]
+	failed := counts[packagecloud.SyncActionPromote][packagecloud.SyncStatusFailed] + counts[packagecloud.SyncActionDelete][packagecloud.SyncStatusFailed]
+
+	fmt.Printf("%s, %s, %d skipped, %d failed, %d already in sync\n", promoted, deleted, skipped, failed, plan.InSync+exists)
+
+	return nil
+}
@@ -2,10 +2,11 @@ package versions
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/Masterminds/semver/v3"
 	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
 	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
 	"github.com/spf13/cobra"
 )
 
@@ -14,8 +15,8 @@ func CompareCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 	var b string
 
 	cmd := &cobra.Command{
-		Use:   "compare <semver a> <semver b>",
-		Short: "Compares semantic version 'a' to semantic version 'b'",
+		Use:   "compare <version a> <version b>",
+		Short: "Compares version 'a' to version 'b'",
 		Args: func(cmd *cobra.Command, args []string) error {
 			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
 
@@ -31,21 +32,30 @@ func CompareCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
 
-			semverA, err := semver.NewVersion(a)
+			schemeName, err := cmd.Flags().GetString(flagScheme)
 			if err != nil {
-				return newErrWithUsage(fmt.Sprintf("invalid semver a: %s", err))
+				return err
 			}
 
-			semverB, err := semver.NewVersion(b)
+			scheme := types.SemverScheme
+			if schemeName != "" {
+				scheme, err = types.GetVersionScheme(schemeName)
+				if err != nil {
+					return newErrWithUsage(err.Error())
+				}
+			}
+
+			c, err := scheme.Compare(a, b)
 			if err != nil {
-				return newErrWithUsage(fmt.Sprintf("invalid semver b: %s", err))
+				return newErrWithUsage(fmt.Sprintf("invalid version: %s", err))
 			}
 
-			if semverA.Equal(semverB) {
+			switch c {
+			case 0:
 				fmt.Println("equal")
-			} else if semverA.GreaterThan(semverB) {
+			case 1:
 				fmt.Println("greater")
-			} else {
+			default:
 				fmt.Println("lesser")
 			}
 
@@ -58,6 +68,7 @@ func CompareCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
 	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
 	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
+	cmd.Flags().String(flagScheme, "", fmt.Sprintf("version scheme used to compare the versions - %s (default: semver)", strings.Join(types.VersionSchemeNames(), ", ")))
 
 	return cmd
 }
@@ -14,6 +14,9 @@ func HelpCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 	cmd.AddCommand(ListCommand(getClientFn))
 	cmd.AddCommand(LatestVersionCommand(getClientFn))
 	cmd.AddCommand(PreviousVersionCommand(getClientFn))
+	cmd.AddCommand(NthVersionCommand(getClientFn))
+	cmd.AddCommand(NextVersionCommand(getClientFn))
+	cmd.AddCommand(MatrixCommand(getClientFn))
 	cmd.AddCommand(CompareCommand(getClientFn))
 
 	return cmd
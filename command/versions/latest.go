@@ -55,13 +55,36 @@ func LatestVersionCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return err
 			}
 
+			schemeName, err := cmd.Flags().GetString(flagScheme)
+			if err != nil {
+				return err
+			}
+
+			scheme, err := versionScheme(schemeName)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			constraint, err := cmd.Flags().GetString(flagConstraint)
+			if err != nil {
+				return err
+			}
+
+			includePrereleases, err := cmd.Flags().GetBool(flagIncludePrereleases)
+			if err != nil {
+				return err
+			}
+
 			options := packagecloud.ListVersionsOptions{
-				Repo:        repo,
-				PackageName: name,
-				Filter:      filter,
-				Dist:        dist,
-				Arch:        arch,
-				PerPage:     perPage,
+				Repo:               repo,
+				PackageName:        name,
+				Filter:             filter,
+				Dist:               dist,
+				Arch:               arch,
+				PerPage:            perPage,
+				Scheme:             scheme,
+				Constraint:         constraint,
+				IncludePrereleases: includePrereleases,
 			}
 
 			if err := options.Validate(); err != nil {
@@ -88,6 +111,9 @@ func LatestVersionCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
 	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
 	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
+	cmd.Flags().String(flagScheme, "", schemeUsage())
+	cmd.Flags().StringP(flagConstraint, shortFlagConstraint, "", "only consider versions matching this constraint (i.e. '~1.4', '<2.0.0')")
+	cmd.Flags().Bool(flagIncludePrereleases, defaultIncludePrereleases, "consider pre-release versions")
 
 	return cmd
 }
@@ -4,13 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"sort"
-	"strconv"
+	"strings"
 
-	"github.com/Masterminds/semver/v3"
 	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/output"
 	"github.com/amdprophet/packagecloud-go/packagecloud"
-	"github.com/olekukonko/tablewriter"
+	"github.com/amdprophet/packagecloud-go/types"
 	"github.com/spf13/cobra"
 )
 
@@ -31,12 +30,44 @@ const (
 
 	flagPerPage      = "per-page"
 	shortFlagPerPage = "p"
+
+	flagScheme = "scheme"
+
+	flagConstraint      = "constraint"
+	shortFlagConstraint = "c"
+
+	flagIncludePrereleases = "include-prereleases"
+
+	defaultIncludePrereleases = false
+
+	flagStrict = "strict"
+
+	defaultStrict = false
 )
 
+// versionScheme returns the version scheme with the given name, or nil if
+// name is empty so that the scheme is chosen from the package type.
+func versionScheme(name string) (types.VersionScheme, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return types.GetVersionScheme(name)
+}
+
+func schemeUsage() string {
+	return fmt.Sprintf("version scheme used to order versions - %s (default: chosen from the package type)", strings.Join(types.VersionSchemeNames(), ", "))
+}
+
 func ListCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 	var repo packagecloud.Repo
 	var name string
 
+	// versions and keys are set to the listed versions and their keys
+	// before they are sorted by the version column.
+	var versions types.PackageVersions
+	keys := map[*types.PackageVersion]string{}
+	columns := listColumns(&name, &versions, keys)
+
 	cmd := &cobra.Command{
 		Use:   "list <user/repo> <package name>",
 		Short: "List all versions of packages with a given name in a given repository",
@@ -80,6 +111,16 @@ func ListCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return err
 			}
 
+			schemeName, err := cmd.Flags().GetString(flagScheme)
+			if err != nil {
+				return err
+			}
+
+			optionsScheme, err := versionScheme(schemeName)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
 			options := packagecloud.ListVersionsOptions{
 				Repo:        repo,
 				PackageName: name,
@@ -87,6 +128,7 @@ func ListCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				Dist:        dist,
 				Arch:        arch,
 				PerPage:     perPage,
+				Scheme:      optionsScheme,
 			}
 
 			if err := options.Validate(); err != nil {
@@ -103,12 +145,34 @@ func ListCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return fmt.Errorf("failed to parse format: %s", err)
 			}
 
-			versions, err := client.ListVersions(options)
+			strict, err := cmd.Flags().GetBool(flagStrict)
+			if err != nil {
+				return err
+			}
+
+			printer, err := output.NewPrinterFromFlags(cmd, format, columns, defaultListColumns, nil)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			versions, err = client.ListVersions(options)
 			if err != nil {
 				return fmt.Errorf("failed to list versions: %w", err)
 			}
 
-			if format == "json" {
+			valid, invalid := versions.Sorted()
+			if len(invalid) > 0 {
+				invalidErr := versions.InvalidVersionsError(invalid)
+				if strict {
+					return fmt.Errorf("failed to parse versions: %w", invalidErr)
+				}
+				fmt.Fprintf(os.Stderr, "Warning: %d version(s) are not valid %s versions and are listed last in natural order: %s\n",
+					len(invalid), invalidErr.Scheme, strings.Join(invalid, ", "))
+			}
+
+			// JSON output is keyed by version unless columns or sorting are
+			// asked for.
+			if format == output.FormatJSON && !printer.Selected() && !printer.Sorted() {
 				bytes, err := json.Marshal(versions)
 				if err != nil {
 					return fmt.Errorf("failed to marshal versions: %w", err)
@@ -117,40 +181,56 @@ func ListCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
 				return nil
 			}
 
-			keys := make([]*semver.Version, 0, len(versions))
-			for key := range versions {
-				version, err := semver.NewVersion(key)
-				if err != nil {
-					return fmt.Errorf("failed to parse version %s: %w", key, err)
-				}
-
-				keys = append(keys, version)
+			var rows []interface{}
+			for _, key := range append(valid, invalid...) {
+				keys[versions.Versions[key]] = key
+				rows = append(rows, versions.Versions[key])
 			}
-			sort.Sort(sort.Reverse(semver.Collection(keys)))
-
-			table := tablewriter.NewWriter(os.Stdout)
-			table.SetHeader([]string{"Name", "Version", "Number of packages"})
-			table.SetAutoMergeCells(false)
 
-			for _, key := range keys {
-				row := []string{
-					options.PackageName,
-					key.String(),
-					strconv.Itoa(versions[key.String()]),
-				}
-				table.Append(row)
-			}
-			table.Render()
-
-			return nil
+			return printer.Print(os.Stdout, rows)
 		},
 	}
 
-	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - table or json")
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, output.FormatUsage)
 	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
 	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
 	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
 	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
+	cmd.Flags().String(flagScheme, "", schemeUsage())
+	cmd.Flags().Bool(flagStrict, defaultStrict, "fail if any version is not valid in the version scheme instead of listing it last")
+	output.AddFlags(cmd, columns)
 
 	return cmd
 }
+
+var defaultListColumns = []string{"name", "version", "epoch", "release", "distros", "architectures", "package_count", "latest_created_at"}
+
+// listColumns returns the columns of versions list for the package with the
+// given name, comparing versions by their keys with the schemes of versions.
+func listColumns(name *string, versions *types.PackageVersions, keys map[*types.PackageVersion]string) output.Columns {
+	columns := output.Columns{{
+		Name:   "name",
+		Header: "Name",
+		Value: func(row interface{}) interface{} {
+			return *name
+		},
+	}}
+
+	for _, c := range output.StructColumns(types.PackageVersion{}) {
+		switch c.Name {
+		case "version":
+			c.Compare = func(a, b interface{}) int {
+				return versions.CompareVersionsLenient(keys[a.(*types.PackageVersion)], keys[b.(*types.PackageVersion)])
+			}
+		case "package_count":
+			c.Header = "Number of packages"
+			c.Aliases = []string{"packages"}
+		case "latest_created_at":
+			c.Header = "Latest upload"
+			c.Aliases = []string{"created", "uploaded"}
+		}
+		columns = append(columns, c)
+	}
+
+	return columns
+}
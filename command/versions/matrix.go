@@ -0,0 +1,229 @@
+package versions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagRequire      = "require"
+	shortFlagRequire = "r"
+)
+
+type matrixVersion struct {
+	Version  string         `json:"version"`
+	Packages map[string]int `json:"packages"`
+	Missing  []string       `json:"missing"`
+}
+
+func MatrixCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+	var name string
+
+	cmd := &cobra.Command{
+		Use:     "matrix <user/repo> <package name>",
+		Short:   "Show which versions of a package exist for each distro and architecture",
+		Example: "matrix ecorp/production ecorp-agent --constraint 3.2.0 --require ubuntu/jammy:amd64,ubuntu/jammy:arm64,el/9:x86_64",
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 2 {
+				return newErrWithUsage("requires exactly 2 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			name = args[1]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			perPage, err := cmd.Flags().GetString(flagPerPage)
+			if err != nil {
+				return err
+			}
+
+			schemeName, err := cmd.Flags().GetString(flagScheme)
+			if err != nil {
+				return err
+			}
+
+			scheme, err := versionScheme(schemeName)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			constraint, err := cmd.Flags().GetString(flagConstraint)
+			if err != nil {
+				return err
+			}
+
+			includePrereleases, err := cmd.Flags().GetBool(flagIncludePrereleases)
+			if err != nil {
+				return err
+			}
+
+			requireFlag, err := cmd.Flags().GetStringSlice(flagRequire)
+			if err != nil {
+				return err
+			}
+
+			var required []types.Platform
+			for _, s := range requireFlag {
+				platform, err := types.ParsePlatform(s)
+				if err != nil {
+					return newErrWithUsage(err.Error())
+				}
+				required = append(required, platform)
+			}
+
+			format, err := cmd.Flags().GetString(flagFormat)
+			if err != nil {
+				return fmt.Errorf("failed to parse format: %s", err)
+			}
+
+			options := packagecloud.ListVersionsOptions{
+				Repo:               repo,
+				PackageName:        name,
+				Filter:             filter,
+				Dist:               dist,
+				Arch:               arch,
+				PerPage:            perPage,
+				Scheme:             scheme,
+				Constraint:         constraint,
+				IncludePrereleases: includePrereleases,
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			matrix, err := client.VersionMatrix(options)
+			if err != nil {
+				return fmt.Errorf("failed to build version matrix: %w", err)
+			}
+			matrix.Require(required)
+
+			if len(matrix.Versions) == 0 {
+				return fmt.Errorf("no versions of %s found in %s", name, repo)
+			}
+
+			var incomplete []string
+			rows := make([]matrixVersion, 0, len(matrix.Versions))
+			for _, version := range matrix.Versions {
+				row := matrixVersion{
+					Version:  version,
+					Packages: map[string]int{},
+					Missing:  []string{},
+				}
+				for _, platform := range matrix.Platforms {
+					row.Packages[platform.String()] = matrix.Count(version, platform)
+				}
+				for _, platform := range matrix.Missing(version, required) {
+					row.Missing = append(row.Missing, platform.String())
+				}
+				if len(row.Missing) > 0 {
+					incomplete = append(incomplete, fmt.Sprintf("%s (missing %s)", version, strings.Join(row.Missing, ", ")))
+				}
+				rows = append(rows, row)
+			}
+
+			if format == "json" {
+				bytes, err := json.Marshal(struct {
+					Versions []matrixVersion `json:"versions"`
+					Complete bool            `json:"complete"`
+				}{
+					Versions: rows,
+					Complete: len(incomplete) == 0,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to marshal version matrix: %w", err)
+				}
+				fmt.Println(string(bytes))
+			} else {
+				printMatrix(matrix, rows)
+			}
+
+			if len(incomplete) > 0 {
+				return fmt.Errorf("required distros/architectures are missing for %d version(s): %s", len(incomplete), strings.Join(incomplete, "; "))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFormat, shortFlagFormat, defaultFormat, "output format to use - table or json")
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
+	cmd.Flags().String(flagScheme, "", schemeUsage())
+	cmd.Flags().StringP(flagConstraint, shortFlagConstraint, "", "only show versions matching this constraint (i.e. '3.2.0', '~3.2')")
+	cmd.Flags().Bool(flagIncludePrereleases, defaultIncludePrereleases, "show pre-release versions")
+	cmd.Flags().StringSliceP(flagRequire, shortFlagRequire, nil, "distro[:arch] every version must exist for (i.e. ubuntu/jammy:amd64, el/9, :arm64), may be repeated")
+
+	return cmd
+}
+
+func printMatrix(matrix *types.VersionMatrix, rows []matrixVersion) {
+	header := []string{"Version"}
+	for _, platform := range matrix.Platforms {
+		header = append(header, platform.String())
+	}
+	header = append(header, "Missing")
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetAutoMergeCells(false)
+	table.SetAutoFormatHeaders(false)
+
+	for _, row := range rows {
+		cells := []string{row.Version}
+		for _, platform := range matrix.Platforms {
+			count := row.Packages[platform.String()]
+			if count == 0 {
+				cells = append(cells, "-")
+			} else {
+				cells = append(cells, strconv.Itoa(count))
+			}
+		}
+		cells = append(cells, strings.Join(row.Missing, ", "))
+		table.Append(cells)
+	}
+	table.Render()
+}
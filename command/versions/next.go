@@ -0,0 +1,158 @@
+package versions
+
+import (
+	"fmt"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagBump      = "bump"
+	shortFlagBump = "b"
+
+	defaultBump = "patch"
+
+	flagPreID = "pre-id"
+
+	flagCheck = "check"
+)
+
+func NextVersionCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+	var name string
+
+	cmd := &cobra.Command{
+		Use:     "next <user/repo> <package name>",
+		Short:   "Suggest the next version of a package from the versions in a given repository",
+		Example: "next ecorp/production ecorp-agent --bump prerelease --pre-id rc",
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 2 {
+				return newErrWithUsage("requires exactly 2 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			name = args[1]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			perPage, err := cmd.Flags().GetString(flagPerPage)
+			if err != nil {
+				return err
+			}
+
+			schemeName, err := cmd.Flags().GetString(flagScheme)
+			if err != nil {
+				return err
+			}
+
+			scheme, err := versionScheme(schemeName)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			constraint, err := cmd.Flags().GetString(flagConstraint)
+			if err != nil {
+				return err
+			}
+
+			bumpFlag, err := cmd.Flags().GetString(flagBump)
+			if err != nil {
+				return err
+			}
+
+			bump, err := types.ParseVersionBump(bumpFlag)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			preID, err := cmd.Flags().GetString(flagPreID)
+			if err != nil {
+				return err
+			}
+
+			candidate, err := cmd.Flags().GetString(flagCheck)
+			if err != nil {
+				return err
+			}
+
+			options := packagecloud.ListVersionsOptions{
+				Repo:        repo,
+				PackageName: name,
+				Filter:      filter,
+				Dist:        dist,
+				Arch:        arch,
+				PerPage:     perPage,
+				Scheme:      scheme,
+				Constraint:  constraint,
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			if candidate != "" {
+				if err := client.CheckNextVersion(options, candidate); err != nil {
+					return fmt.Errorf("version check failed: %w", err)
+				}
+
+				fmt.Println(candidate)
+
+				return nil
+			}
+
+			next, err := client.NextVersion(options, bump, preID)
+			if err != nil {
+				return fmt.Errorf("failed to get next version: %w", err)
+			}
+
+			fmt.Println(next)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
+	cmd.Flags().String(flagScheme, "", schemeUsage())
+	cmd.Flags().StringP(flagConstraint, shortFlagConstraint, "", "only consider versions matching this constraint (i.e. '~1.4', '<2.0.0')")
+	cmd.Flags().StringP(flagBump, shortFlagBump, defaultBump, "part of the version to increment - major, minor, patch or prerelease")
+	cmd.Flags().String(flagPreID, "", fmt.Sprintf("identifier to use for pre-releases (default: %s)", types.DefaultPrereleaseID))
+	cmd.Flags().String(flagCheck, "", "instead of suggesting a version, fail unless this version is newer than the latest published version")
+
+	return cmd
+}
@@ -0,0 +1,128 @@
+package versions
+
+import (
+	"fmt"
+	"strconv"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/spf13/cobra"
+)
+
+func NthVersionCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+	var name string
+	var n int
+
+	cmd := &cobra.Command{
+		Use:     "nth <user/repo> <package name> <n>",
+		Short:   "Show the version of a package n releases before the latest in a given repository",
+		Example: "nth ecorp/production ecorp-agent 2 --constraint '~1.4'",
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) != 3 {
+				return newErrWithUsage("requires exactly 3 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			name = args[1]
+
+			if arg, err := strconv.Atoi(args[2]); err != nil || arg < 0 {
+				return newErrWithUsage(fmt.Sprintf("invalid n: %s (must be 0 for the latest version, 1 for the previous version, etc.)", args[2]))
+			} else {
+				n = arg
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			perPage, err := cmd.Flags().GetString(flagPerPage)
+			if err != nil {
+				return err
+			}
+
+			schemeName, err := cmd.Flags().GetString(flagScheme)
+			if err != nil {
+				return err
+			}
+
+			scheme, err := versionScheme(schemeName)
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			constraint, err := cmd.Flags().GetString(flagConstraint)
+			if err != nil {
+				return err
+			}
+
+			includePrereleases, err := cmd.Flags().GetBool(flagIncludePrereleases)
+			if err != nil {
+				return err
+			}
+
+			options := packagecloud.ListVersionsOptions{
+				Repo:               repo,
+				PackageName:        name,
+				Filter:             filter,
+				Dist:               dist,
+				Arch:               arch,
+				PerPage:            perPage,
+				Scheme:             scheme,
+				Constraint:         constraint,
+				IncludePrereleases: includePrereleases,
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			version, err := client.NthVersion(options, n)
+			if err != nil {
+				return fmt.Errorf("failed to get version: %w", err)
+			}
+
+			fmt.Println(version)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
+	cmd.Flags().String(flagScheme, "", schemeUsage())
+	cmd.Flags().StringP(flagConstraint, shortFlagConstraint, "", "only consider versions matching this constraint (i.e. '~1.4', '<2.0.0')")
+	cmd.Flags().Bool(flagIncludePrereleases, defaultIncludePrereleases, "consider pre-release versions")
+
+	return cmd
+}
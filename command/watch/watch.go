@@ -0,0 +1,319 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	commanderrors "github.com/amdprophet/packagecloud-go/command/errors"
+	"github.com/amdprophet/packagecloud-go/command/search"
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagQuery      = "query"
+	shortFlagQuery = "q"
+
+	flagFilter      = "filter"
+	shortFlagFilter = "i"
+
+	flagDist      = "dist"
+	shortFlagDist = "d"
+
+	flagArch      = "arch"
+	shortFlagArch = "a"
+
+	flagPerPage      = "per-page"
+	shortFlagPerPage = "p"
+
+	flagInterval = "interval"
+
+	defaultInterval = 30 * time.Second
+
+	flagStateFile = "state-file"
+
+	flagExec = "exec"
+
+	flagEmitExisting = "emit-existing"
+
+	flagOnce = "once"
+)
+
+// watchStateFlags are the flags that don't change which packages are
+// watched, and so are left out of the default state file name.
+var watchStateFlags = map[string]bool{
+	flagInterval:     true,
+	flagStateFile:    true,
+	flagExec:         true,
+	flagEmitExisting: true,
+	flagOnce:         true,
+	flagPerPage:      true,
+}
+
+func WatchCommand(getClientFn packagecloud.GetClientFn) *cobra.Command {
+	var repo packagecloud.Repo
+	var searchQuery *packagecloud.Query
+
+	cmd := &cobra.Command{
+		Use:   "watch user/repo [query]",
+		Short: "Stream packages as they are uploaded and indexed",
+		Example: "watch ecorp/staging 'name:ecorp-agent' --interval 1m\n" +
+			"watch ecorp/staging -d ubuntu/jammy --exec 'run-tests {{quote .Package.Filename}}'",
+		Long: `Stream packages as they are uploaded and indexed.
+
+The repository is searched every --interval and an event is printed as a line
+of JSON for each package that is new since the previous search ("new") or that
+has been indexed since it was first seen ("indexed"). Packages are filtered
+with the same flags and query as the search command.
+
+The packages seen are saved to --state-file after each search, so a restarted
+watch only reports what changed while it was stopped. By default the state is
+kept in the user cache directory, in a file specific to the repository and
+search. The first search of a new state only records the existing packages
+unless --emit-existing is set.
+
+--exec runs a shell command for each event. The command is a Go template
+executed with the event (i.e. {{.Type}}, {{.Package.Filename}},
+{{.Package.Version}}); use the quote function to quote values for the shell.
+The event is also passed as JSON in the PACKAGECLOUD_EVENT environment
+variable. The output of the command is written to stderr and a failed command
+does not stop the watch.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			if len(args) < 1 || len(args) > 2 {
+				return newErrWithUsage("requires 1 or 2 arguments")
+			}
+
+			if arg, err := packagecloud.NewRepoFromString(args[0]); err != nil {
+				return newErrWithUsage(fmt.Sprintf("invalid repo: %s", err))
+			} else {
+				repo = arg
+			}
+
+			if len(args) == 2 {
+				q, err := packagecloud.ParseQuery(args[1], time.Now())
+				if err != nil {
+					return err
+				}
+				searchQuery = q
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newErrWithUsage := commanderrors.NewErrorWithUsageFactory(cmd.Help)
+
+			query, err := cmd.Flags().GetString(flagQuery)
+			if err != nil {
+				return err
+			}
+
+			filter, err := cmd.Flags().GetString(flagFilter)
+			if err != nil {
+				return err
+			}
+
+			dist, err := cmd.Flags().GetString(flagDist)
+			if err != nil {
+				return err
+			}
+
+			arch, err := cmd.Flags().GetString(flagArch)
+			if err != nil {
+				return err
+			}
+
+			perPage, err := cmd.Flags().GetString(flagPerPage)
+			if err != nil {
+				return err
+			}
+
+			interval, err := cmd.Flags().GetDuration(flagInterval)
+			if err != nil {
+				return err
+			}
+
+			statePath, err := cmd.Flags().GetString(flagStateFile)
+			if err != nil {
+				return err
+			}
+
+			execTemplate, err := cmd.Flags().GetString(flagExec)
+			if err != nil {
+				return err
+			}
+
+			emitExisting, err := cmd.Flags().GetBool(flagEmitExisting)
+			if err != nil {
+				return err
+			}
+
+			once, err := cmd.Flags().GetBool(flagOnce)
+			if err != nil {
+				return err
+			}
+
+			filters, err := search.GetFilters(cmd, time.Now())
+			if err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			if interval <= 0 {
+				return newErrWithUsage(fmt.Sprintf("--%s must be greater than 0", flagInterval))
+			}
+
+			options := packagecloud.SearchOptions{
+				RepoUser: repo.User,
+				RepoName: repo.Name,
+				Query:    query,
+				Filter:   filter,
+				Dist:     dist,
+				Arch:     arch,
+				PerPage:  perPage,
+				Filters:  filters,
+			}
+			if searchQuery != nil {
+				searchQuery.Apply(&options)
+			}
+
+			if err := options.Validate(); err != nil {
+				return newErrWithUsage(err.Error())
+			}
+
+			var tmpl *template.Template
+			if execTemplate != "" {
+				tmpl, err = template.New(flagExec).Funcs(template.FuncMap{"quote": shellQuote}).Parse(execTemplate)
+				if err != nil {
+					return newErrWithUsage(fmt.Sprintf("invalid --%s template: %s", flagExec, err))
+				}
+			}
+
+			if statePath == "" {
+				statePath, err = defaultStatePath(cmd, repo, args)
+				if err != nil {
+					return err
+				}
+			}
+
+			state, err := packagecloud.LoadWatchState(statePath)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFn()
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			encoder := json.NewEncoder(os.Stdout)
+			err = client.Watch(ctx, options, packagecloud.WatchOptions{
+				Interval:     interval,
+				State:        state,
+				StatePath:    statePath,
+				EmitExisting: emitExisting,
+				Once:         once,
+				OnEvent: func(event packagecloud.WatchEvent) error {
+					if err := encoder.Encode(event); err != nil {
+						return err
+					}
+					if tmpl != nil {
+						if err := runEventCommand(ctx, tmpl, event); err != nil {
+							fmt.Fprintf(os.Stderr, "Warning: command for %s event of %s failed: %s\n", event.Type, event.Package.Filename, err)
+						}
+					}
+					return nil
+				},
+				OnError: func(err error) {
+					fmt.Fprintf(os.Stderr, "Warning: %s, retrying in %s\n", err, interval)
+				},
+			})
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringP(flagQuery, shortFlagQuery, "", "search string to search for package filename(s)")
+	cmd.Flags().StringP(flagFilter, shortFlagFilter, "", "name of package type to search for packages (ignored when --dist is set)")
+	cmd.Flags().StringP(flagDist, shortFlagDist, "", "name of the distribution to filter packages by (overrides --filter)")
+	cmd.Flags().StringP(flagArch, shortFlagArch, "", "architecture to filter packages by (alpine/rpm/debian only)")
+	cmd.Flags().StringP(flagPerPage, shortFlagPerPage, "256", "number of packages to return from the result set with each request")
+	cmd.Flags().Duration(flagInterval, defaultInterval, "delay between searches")
+	cmd.Flags().String(flagStateFile, "", "file to save the packages seen to (defaults to a file in the user cache directory)")
+	cmd.Flags().String(flagExec, "", "shell command template to run for each event")
+	cmd.Flags().Bool(flagEmitExisting, false, "report the packages that already exist when starting with a new state")
+	cmd.Flags().Bool(flagOnce, false, "search once and exit instead of watching")
+	search.AddFilterFlags(cmd)
+
+	return cmd
+}
+
+// defaultStatePath returns the state file for watching repo with the
+// command's arguments and search flags, so that watches of different
+// searches don't share a state.
+func defaultStatePath(cmd *cobra.Command, repo packagecloud.Repo, args []string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find a directory for the watch state, use --%s: %w", flagStateFile, err)
+	}
+
+	key := append([]string{}, args...)
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if !watchStateFlags[f.Name] {
+			flags = append(flags, f.Name+"="+f.Value.String())
+		}
+	})
+	sort.Strings(flags)
+	key = append(key, flags...)
+
+	sum := sha256.Sum256([]byte(strings.Join(key, "\x00")))
+	name := fmt.Sprintf("%s_%s-%s.json", repo.User, repo.Name, hex.EncodeToString(sum[:])[:12])
+
+	return filepath.Join(cacheDir, "packagecloud", "watch", name), nil
+}
+
+// runEventCommand runs the shell command rendered from tmpl for event.
+func runEventCommand(ctx context.Context, tmpl *template.Template, event packagecloud.WatchEvent) error {
+	var script bytes.Buffer
+	if err := tmpl.Execute(&script, event); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	c := exec.CommandContext(ctx, "sh", "-c", script.String())
+	c.Env = append(os.Environ(), "PACKAGECLOUD_EVENT="+string(data))
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+
+	return c.Run()
+}
+
+// shellQuote quotes s as a single word for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
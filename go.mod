@@ -12,6 +12,7 @@ require (
 	github.com/spf13/cobra v1.0.0
 	github.com/spf13/pflag v1.0.3
 	github.com/spf13/viper v1.4.0
+	gopkg.in/yaml.v2 v2.2.2
 )
 
 require (
@@ -27,5 +28,4 @@ require (
 	github.com/spf13/jwalterweatherman v1.0.0 // indirect
 	golang.org/x/sys v0.0.0-20190215142949-d0b11bdaac8a // indirect
 	golang.org/x/text v0.3.0 // indirect
-	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
@@ -14,6 +14,10 @@ import (
 
 type GetClientFn func() (*Client, error)
 
+// GetProfileClientFn returns a client configured from the named profile. An
+// empty profile name returns the default client.
+type GetProfileClientFn func(profile string) (*Client, error)
+
 type APIResponse struct {
 	Body      []byte
 	LinkGroup link.Group
@@ -31,18 +35,31 @@ func NewClient(config Config) *Client {
 	}
 }
 
+// DryRun returns true if the client has been configured to refuse any
+// request that would modify a repository.
+func (c *Client) DryRun() bool {
+	return c.config.DryRun
+}
+
 func (c *Client) getURL(path *url.URL) *url.URL {
 	baseURL, _ := url.Parse(c.config.ServiceURL)
 	return baseURL.ResolveReference(path)
 }
 
 func (c *Client) apiRequest(method string, url string, payload io.Reader, contentType string) (*APIResponse, error) {
+	if c.config.DryRun && method != http.MethodGet {
+		return nil, ErrDryRun
+	}
+
 	req, err := http.NewRequest(method, url, payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %s", err)
 	}
 
 	req.Header.Set("Accept", contentType)
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
 	req.SetBasicAuth(c.config.Token, "")
 
 	client := &http.Client{}
@@ -57,18 +74,21 @@ func (c *Client) apiRequest(method string, url string, payload io.Reader, conten
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var httpErr error
 	switch resp.StatusCode {
 	case 401:
 		return nil, ErrUnauthenticated
 	case 402:
-		httpErr = getResponseError(body, "payment required", ErrPaymentRequired)
+		if err := getResponseError(body, "payment required", ErrPaymentRequired); err == ErrPaymentRequired {
+			return nil, err
+		}
 	case 404:
 		return nil, ErrNotFound
 	case 422:
-		httpErr = getResponseError(body, "has already been taken", ErrPackageAlreadyExists)
+		if err := getResponseError(body, "has already been taken", ErrPackageAlreadyExists); err == ErrPackageAlreadyExists {
+			return nil, err
+		}
 	}
-	if httpErr != nil {
+	if resp.StatusCode >= 400 {
 		return nil, fmt.Errorf("api responded with error: %s", string(body))
 	}
 
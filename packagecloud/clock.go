@@ -0,0 +1,24 @@
+package packagecloud
+
+import "time"
+
+// Clock abstracts the passage of time so that polling logic can be tested
+// without actually sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
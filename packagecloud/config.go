@@ -10,6 +10,11 @@ type Config struct {
 	ServiceURL string `mapstructure:"url"`
 	Token      string `mapstructure:"token"`
 	Verbose    bool   `mapstructure:"verbose"`
+
+	// DryRun prevents the client from performing any request that would
+	// modify a repository. Read-only requests are still performed so that
+	// the outcome of a mutating operation can be resolved and reported.
+	DryRun bool `mapstructure:"dry-run"`
 }
 
 func (c Config) Validate() error {
@@ -0,0 +1,307 @@
+package packagecloud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+type CopyStatus string
+
+const (
+	// CopyStatusCopied indicates that the package was downloaded from the
+	// source and pushed to the destination.
+	CopyStatusCopied CopyStatus = "copied"
+
+	// CopyStatusPlanned indicates that the package would have been copied if
+	// the destination client was not in dry-run mode.
+	CopyStatusPlanned CopyStatus = "planned"
+
+	// CopyStatusFailed indicates that copying the package failed.
+	CopyStatusFailed CopyStatus = "failed"
+
+	// CopyStatusSkipped indicates that the package was not copied, either
+	// because an identical package already exists in the destination or
+	// because an earlier copy failed.
+	CopyStatusSkipped CopyStatus = "skipped"
+)
+
+// CopyResult describes the outcome of copying a single package.
+type CopyResult struct {
+	// Source is the repository the package was copied from.
+	Source Repo
+
+	// Destination is the repository the package was copied to.
+	Destination Repo
+
+	// Package is the package in the source repository.
+	Package types.PackageFragment
+
+	// Distro is the distro the package was pushed to in the destination.
+	Distro string
+
+	// Status is the outcome of the copy.
+	Status CopyStatus
+
+	// Exists specifies whether or not the package already exists in the
+	// destination repository.
+	Exists bool
+
+	// Err is the error that caused the copy to fail, if any.
+	Err error
+}
+
+func (r CopyResult) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Source      string                `json:"source"`
+		Destination string                `json:"destination"`
+		Package     types.PackageFragment `json:"package"`
+		Distro      string                `json:"distro"`
+		Status      CopyStatus            `json:"status"`
+		Exists      bool                  `json:"exists"`
+		Error       string                `json:"error,omitempty"`
+	}{
+		Source:      r.Source.String(),
+		Destination: r.Destination.String(),
+		Package:     r.Package,
+		Distro:      r.Distro,
+		Status:      r.Status,
+		Exists:      r.Exists,
+		Error:       errMsg,
+	})
+}
+
+type CopyOptions struct {
+	// Distro, if set, pushes every package to this distro instead of the
+	// distro it has in the source repository.
+	Distro *Distro
+
+	// Concurrency is the maximum number of packages to copy at the same
+	// time. Defaults to 1.
+	Concurrency int
+
+	// ContinueOnError continues copying the remaining packages after a copy
+	// fails. When false, packages that have not been copied by the time a
+	// copy fails are skipped.
+	ContinueOnError bool
+
+	// OnResult, if set, is called after each package has been copied, or
+	// has failed to be copied. It may be called from multiple goroutines at
+	// the same time.
+	OnResult func(CopyResult)
+}
+
+// Copy searches the source repository for packages matching search and
+// copies each of them to dst by downloading the package file from the
+// source client, verifying its checksum and pushing it with the destination
+// client. The clients may point at different packagecloud instances or
+// accounts; distros are resolved through the destination's own
+// distributions. Packages that already exist in the destination with the
+// same checksum are skipped. A result is returned for every package and, if
+// any copy fails, a *CopyFailedError is returned along with the results.
+func Copy(srcClient *Client, search SearchOptions, dstClient *Client, dst Repo, opts CopyOptions) ([]CopyResult, error) {
+	if err := search.Validate(); err != nil {
+		return nil, fmt.Errorf("search options validation failed: %w", err)
+	}
+	if err := dst.Validate(); err != nil {
+		return nil, fmt.Errorf("destination repository validation failed: %w", err)
+	}
+	if opts.Distro != nil {
+		if err := opts.Distro.Validate(); err != nil {
+			return nil, fmt.Errorf("distro validation failed: %w", err)
+		}
+	}
+
+	src := NewRepo(search.RepoUser, search.RepoName)
+
+	packages, err := srcClient.Search(search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search source repository: %w", err)
+	}
+
+	packageTypes, err := dstClient.GetDistributions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch destination distributions: %w", err)
+	}
+
+	results := make([]CopyResult, len(packages))
+
+	forEach(len(packages), opts.Concurrency, opts.ContinueOnError, func(i int) bool {
+		results[i] = copyPackage(srcClient, src, dstClient, dst, packages[i], packageTypes, opts.Distro)
+
+		if opts.OnResult != nil {
+			opts.OnResult(results[i])
+		}
+
+		return results[i].Err == nil
+	}, func(i int) {
+		results[i] = CopyResult{
+			Source:      src,
+			Destination: dst,
+			Package:     packages[i],
+			Distro:      packages[i].DistroVersion,
+			Status:      CopyStatusSkipped,
+		}
+		if opts.Distro != nil {
+			results[i].Distro = opts.Distro.String()
+		}
+	})
+
+	var failures []CopyResult
+	for _, result := range results {
+		if result.Status == CopyStatusFailed {
+			failures = append(failures, result)
+		}
+	}
+	if len(failures) > 0 {
+		return results, &CopyFailedError{
+			Failures: failures,
+			Total:    len(results),
+		}
+	}
+
+	return results, nil
+}
+
+func copyPackage(srcClient *Client, src Repo, dstClient *Client, dst Repo, pkg types.PackageFragment, packageTypes types.PackageTypes, distroOverride *Distro) CopyResult {
+	result := CopyResult{
+		Source:      src,
+		Destination: dst,
+		Package:     pkg,
+		Distro:      pkg.DistroVersion,
+	}
+
+	fail := func(err error) CopyResult {
+		result.Status = CopyStatusFailed
+		result.Err = err
+		return result
+	}
+
+	distro, err := NewDistroFromString(pkg.DistroVersion)
+	if err != nil {
+		return fail(fmt.Errorf("invalid distro: %w", err))
+	}
+	if distroOverride != nil {
+		distro = *distroOverride
+		result.Distro = distro.String()
+	}
+
+	if _, ok := packageTypes[pkg.Type]; !ok {
+		return fail(fmt.Errorf("destination does not support package type: %s", pkg.Type))
+	}
+	distroID, err := types.GetDistroID(packageTypes[pkg.Type], pkg.Type, distro.Name, distro.Version)
+	if err != nil {
+		return fail(err)
+	}
+
+	details, err := srcClient.GetPackageDetails(pkg)
+	if err != nil {
+		return fail(fmt.Errorf("failed to get package details: %w", err))
+	}
+
+	existing, err := dstClient.FindPackage(dst, distro, pkg.Filename)
+	if err != nil {
+		return fail(fmt.Errorf("failed to search destination repository: %w", err))
+	}
+	if existing != nil {
+		result.Exists = true
+
+		existingDetails, err := dstClient.GetPackageDetails(*existing)
+		if err != nil {
+			return fail(fmt.Errorf("failed to get package details: %w", err))
+		}
+
+		same, err := details.SameChecksum(*existingDetails)
+		if err != nil {
+			return fail(fmt.Errorf("failed to compare checksums: %w", err))
+		}
+		if !same {
+			return fail(ErrChecksumMismatch)
+		}
+
+		result.Status = CopyStatusSkipped
+		return result
+	}
+
+	if dstClient.DryRun() {
+		result.Status = CopyStatusPlanned
+		return result
+	}
+
+	if err := transferPackage(srcClient, *details, dstClient, dst, distroID); err != nil {
+		return fail(err)
+	}
+
+	result.Status = CopyStatusCopied
+	return result
+}
+
+// transferPackage downloads a package to a temporary file, keeping its
+// original filename, and pushes it to the destination repository.
+func transferPackage(srcClient *Client, details types.PackageDetails, dstClient *Client, dst Repo, distroID int) error {
+	dir, err := os.MkdirTemp("", "packagecloud-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, filepath.Base(details.Filename))
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	if err := srcClient.DownloadPackage(details, file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	_, err = dstClient.PushPackage(PushPackageOptions{
+		RepoUser: dst.User,
+		RepoName: dst.Name,
+		DistroID: strconv.Itoa(distroID),
+		FilePath: filePath,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to push package: %w", err)
+	}
+
+	return nil
+}
+
+// Retarget copies every package in repo matching search from one distro to
+// another (i.e. ubuntu/jammy to ubuntu/noble) by downloading each package
+// and pushing it under the distro id of the new distro. The Dist field of
+// search is replaced with from. Packages that already exist in the new
+// distro with the same checksum are skipped.
+func (c *Client) Retarget(repo Repo, from Distro, to Distro, search SearchOptions, opts CopyOptions) ([]CopyResult, error) {
+	if err := repo.Validate(); err != nil {
+		return nil, fmt.Errorf("repository validation failed: %w", err)
+	}
+	if err := from.Validate(); err != nil {
+		return nil, fmt.Errorf("source distro validation failed: %w", err)
+	}
+	if from == to {
+		return nil, errors.New("source and destination distros must be different")
+	}
+
+	search.RepoUser = repo.User
+	search.RepoName = repo.Name
+	search.Dist = from.String()
+	opts.Distro = &to
+
+	return Copy(c, search, c, repo, opts)
+}
@@ -0,0 +1,127 @@
+package packagecloud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// copyDistributions are the distros of the fake servers packages are copied
+// with.
+var copyDistributions = types.PackageTypes{
+	"deb": {{IndexName: "ubuntu", Versions: []types.DistroVersion{{ID: 42, IndexName: "jammy"}, {ID: 43, IndexName: "noble"}}}},
+}
+
+// newCopyServer returns a fake server where user/repo has a single package,
+// agent, whose download contents are body and whose reported checksum is
+// sha256sum.
+func newCopyServer(t *testing.T, body string, sha256sum string) (*fakeServer, types.PackageFragment) {
+	t.Helper()
+
+	pkg := fakePackage("user/repo", "ubuntu/jammy", "amd64", "agent", "1.0")
+	server := newFakeServer(t, map[string]types.PackageFragments{"user/repo": {pkg}})
+	server.addDetails(pkg, types.PackageDetails{
+		Filename:    pkg.Filename,
+		DownloadURL: "/download/" + pkg.Filename,
+		SHA256Sum:   sha256sum,
+	})
+	server.addFile("/download/"+pkg.Filename, body)
+	server.distributions = copyDistributions
+	return server, pkg
+}
+
+func TestCopy(t *testing.T) {
+	body := "package contents"
+	sum := sha256.Sum256([]byte(body))
+	src, pkg := newCopyServer(t, body, hex.EncodeToString(sum[:]))
+	dst := newFakeServer(t, nil)
+	dst.distributions = copyDistributions
+
+	search := SearchOptions{RepoUser: "user", RepoName: "repo", Query: "agent"}
+
+	results, err := Copy(src.client(), search, dst.client(), NewRepo("other", "repo"), CopyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Status != CopyStatusCopied {
+		t.Fatalf("expected %s to be copied, got %+v", pkg.Filename, results)
+	}
+	if dst.pushed[pkg.Filename] != "42" {
+		t.Errorf("expected %s to be pushed to distro id 42, got %v", pkg.Filename, dst.pushed)
+	}
+
+	distro := NewDistro("ubuntu", "noble")
+	if _, err := Copy(src.client(), search, dst.client(), NewRepo("other", "repo"), CopyOptions{Distro: &distro}); err != nil {
+		t.Fatal(err)
+	}
+	if dst.pushed[pkg.Filename] != "43" {
+		t.Errorf("expected %s to be pushed to distro id 43, got %v", pkg.Filename, dst.pushed)
+	}
+}
+
+func TestCopyChecksumMismatch(t *testing.T) {
+	src, _ := newCopyServer(t, "tampered contents", strings.Repeat("0", 64))
+	dst := newFakeServer(t, nil)
+	dst.distributions = copyDistributions
+
+	search := SearchOptions{RepoUser: "user", RepoName: "repo", Query: "agent"}
+
+	_, err := Copy(src.client(), search, dst.client(), NewRepo("other", "repo"), CopyOptions{})
+
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("expected ChecksumError, got %v", err)
+	}
+	if len(dst.pushed) != 0 {
+		t.Errorf("expected nothing to be pushed, got %v", dst.pushed)
+	}
+}
+
+func TestCopySkipsAfterFailure(t *testing.T) {
+	src, pkg := newCopyServer(t, "tampered contents", strings.Repeat("0", 64))
+	extra := fakePackage("user/repo", "ubuntu/jammy", "amd64", "agent-extra", "1.0")
+	src.setPackages("user/repo", types.PackageFragments{pkg, extra})
+	dst := newFakeServer(t, nil)
+	dst.distributions = copyDistributions
+
+	search := SearchOptions{RepoUser: "user", RepoName: "repo", Query: "agent"}
+	distro := NewDistro("ubuntu", "noble")
+
+	results, err := Copy(src.client(), search, dst.client(), NewRepo("other", "repo"), CopyOptions{Distro: &distro})
+	if err == nil {
+		t.Fatal("expected the copy to fail")
+	}
+	if len(results) != 2 || results[1].Status != CopyStatusSkipped {
+		t.Fatalf("expected %s to be skipped, got %+v", extra.Filename, results)
+	}
+	if results[1].Distro != "ubuntu/noble" {
+		t.Errorf("expected skipped %s to report distro ubuntu/noble, got %s", extra.Filename, results[1].Distro)
+	}
+}
+
+func TestRetarget(t *testing.T) {
+	body := "package contents"
+	sum := sha256.Sum256([]byte(body))
+	server, pkg := newCopyServer(t, body, hex.EncodeToString(sum[:]))
+	client := server.client()
+	repo := NewRepo("user", "repo")
+
+	if _, err := client.Retarget(repo, NewDistro("ubuntu", "jammy"), NewDistro("ubuntu", "jammy"), SearchOptions{}, CopyOptions{}); err == nil {
+		t.Error("expected an error when retargeting to the same distro")
+	}
+
+	results, err := client.Retarget(repo, NewDistro("ubuntu", "jammy"), NewDistro("ubuntu", "noble"), SearchOptions{Query: "agent"}, CopyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Status != CopyStatusCopied || results[0].Distro != "ubuntu/noble" {
+		t.Fatalf("expected %s to be retargeted to ubuntu/noble, got %+v", pkg.Filename, results)
+	}
+	if server.pushed[pkg.Filename] != "43" {
+		t.Errorf("expected %s to be pushed to distro id 43, got %v", pkg.Filename, server.pushed)
+	}
+}
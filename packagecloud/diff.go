@@ -0,0 +1,232 @@
+package packagecloud
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+type DiffStatus string
+
+const (
+	// DiffStatusLeftOnly indicates that the package only exists in the left
+	// repository.
+	DiffStatusLeftOnly DiffStatus = "left-only"
+
+	// DiffStatusRightOnly indicates that the package only exists in the
+	// right repository.
+	DiffStatusRightOnly DiffStatus = "right-only"
+
+	// DiffStatusChanged indicates that a package with the same filename
+	// exists in both repositories, but with a different checksum.
+	DiffStatusChanged DiffStatus = "changed"
+)
+
+// DiffEntry is a difference between two repositories.
+type DiffEntry struct {
+	// Status is the kind of difference.
+	Status DiffStatus `json:"status"`
+
+	// Name is the name of the package.
+	Name string `json:"name"`
+
+	// Type is the type of the package.
+	Type string `json:"type"`
+
+	// Version is the version key of the package (see
+	// types.PackageVersionKey), i.e. [epoch:]version[-release].
+	Version string `json:"version"`
+
+	// DistroVersion is the distro version of the package.
+	DistroVersion string `json:"distro_version"`
+
+	// Architecture is the architecture of the package.
+	Architecture string `json:"architecture"`
+
+	// Filename is the filename of the package.
+	Filename string `json:"filename"`
+
+	// LeftChecksum and RightChecksum are the checksums of the package in
+	// each repository (i.e. sha256:2c26b4...), when they differ.
+	LeftChecksum  string `json:"left_checksum,omitempty"`
+	RightChecksum string `json:"right_checksum,omitempty"`
+
+	// Package is the package in the left repository, or in the right one
+	// when it only exists there.
+	Package types.PackageFragment `json:"-"`
+}
+
+// RepoDiff is the outcome of comparing two repositories.
+type RepoDiff struct {
+	Left  Repo
+	Right Repo
+
+	// Entries are the differences between the repositories, ordered by
+	// name, distro version, architecture and version.
+	Entries []DiffEntry
+
+	// Same is the number of packages that exist in both repositories and
+	// are not different.
+	Same int
+}
+
+type DiffOptions struct {
+	// SkipChecksums only compares which packages exist in each repository,
+	// without retrieving the details of the packages that exist in both to
+	// compare their checksums.
+	SkipChecksums bool
+
+	// Concurrency is the maximum number of packages to compare the
+	// checksums of at the same time. Defaults to 1.
+	Concurrency int
+}
+
+// Diff compares the packages matching search in the left and right
+// repositories. The repository in search is ignored. Packages are matched by
+// distro version and filename.
+func (c *Client) Diff(left Repo, right Repo, search SearchOptions, opts DiffOptions) (*RepoDiff, error) {
+	if err := left.Validate(); err != nil {
+		return nil, fmt.Errorf("left repository validation failed: %w", err)
+	}
+	if err := right.Validate(); err != nil {
+		return nil, fmt.Errorf("right repository validation failed: %w", err)
+	}
+
+	find := func(repo Repo) (map[string]types.PackageFragment, error) {
+		options := search
+		options.RepoUser = repo.User
+		options.RepoName = repo.Name
+
+		packages, err := c.findPackages(options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list packages of %s: %w", repo, err)
+		}
+
+		byKey := make(map[string]types.PackageFragment, len(packages))
+		for _, pkg := range packages {
+			byKey[pkg.DistroVersion+"/"+pkg.Filename] = pkg
+		}
+		return byKey, nil
+	}
+
+	leftPackages, err := find(left)
+	if err != nil {
+		return nil, err
+	}
+	rightPackages, err := find(right)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &RepoDiff{
+		Left:    left,
+		Right:   right,
+		Entries: []DiffEntry{},
+	}
+
+	var common [][2]types.PackageFragment
+	for key, pkg := range leftPackages {
+		if other, ok := rightPackages[key]; ok {
+			common = append(common, [2]types.PackageFragment{pkg, other})
+		} else {
+			diff.Entries = append(diff.Entries, newDiffEntry(DiffStatusLeftOnly, pkg))
+		}
+	}
+	for key, pkg := range rightPackages {
+		if _, ok := leftPackages[key]; !ok {
+			diff.Entries = append(diff.Entries, newDiffEntry(DiffStatusRightOnly, pkg))
+		}
+	}
+
+	if opts.SkipChecksums {
+		diff.Same = len(common)
+	} else {
+		changed := make([]*DiffEntry, len(common))
+		errs := make([]error, len(common))
+
+		forEach(len(common), opts.Concurrency, false, func(i int) bool {
+			changed[i], errs[i] = c.diffChecksums(common[i][0], common[i][1])
+			return errs[i] == nil
+		}, func(int) {})
+
+		for i := range common {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			if changed[i] != nil {
+				diff.Entries = append(diff.Entries, *changed[i])
+			} else {
+				diff.Same++
+			}
+		}
+	}
+
+	sortDiffEntries(diff.Entries)
+
+	return diff, nil
+}
+
+func newDiffEntry(status DiffStatus, pkg types.PackageFragment) DiffEntry {
+	return DiffEntry{
+		Status:        status,
+		Name:          pkg.Name,
+		Type:          pkg.Type,
+		Version:       types.PackageVersionKey(types.VersionSchemeForPackageType(pkg.Type), pkg.Epoch, pkg.Version, pkg.Release),
+		DistroVersion: pkg.DistroVersion,
+		Architecture:  pkg.Architecture,
+		Filename:      pkg.Filename,
+		Package:       pkg,
+	}
+}
+
+// diffChecksums returns a changed entry if the checksums of left and right
+// are different, or nil if they are the same.
+func (c *Client) diffChecksums(left types.PackageFragment, right types.PackageFragment) (*DiffEntry, error) {
+	leftDetails, err := c.GetPackageDetails(left)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package details: %w", err)
+	}
+
+	rightDetails, err := c.GetPackageDetails(right)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package details: %w", err)
+	}
+
+	same, err := leftDetails.SameChecksum(*rightDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare checksums of %s: %w", left.Filename, err)
+	}
+	if same {
+		return nil, nil
+	}
+
+	entry := newDiffEntry(DiffStatusChanged, left)
+	leftAlgorithm, leftSum := leftDetails.Checksum()
+	rightAlgorithm, rightSum := rightDetails.Checksum()
+	entry.LeftChecksum = leftAlgorithm + ":" + leftSum
+	entry.RightChecksum = rightAlgorithm + ":" + rightSum
+
+	return &entry, nil
+}
+
+func sortDiffEntries(entries []DiffEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.DistroVersion != b.DistroVersion {
+			return a.DistroVersion < b.DistroVersion
+		}
+		if a.Architecture != b.Architecture {
+			return a.Architecture < b.Architecture
+		}
+		if a.Type == b.Type {
+			if c := types.CompareVersionsLenient(types.VersionSchemeForPackageType(a.Type), a.Version, b.Version); c != 0 {
+				return c < 0
+			}
+		}
+		return a.Filename < b.Filename
+	})
+}
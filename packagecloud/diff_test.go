@@ -0,0 +1,60 @@
+package packagecloud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newDiffServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	return newChecksumServer(t, map[string][][3]string{
+		"ecorp/staging": {
+			{"agent", "1.0.0", "aaa"},
+			{"agent", "1.1.0", "bbb"},
+			{"agent", "1.10.0", "ccc"},
+		},
+		"ecorp/production": {
+			{"agent", "1.0.0", "aaa"},
+			{"agent", "1.1.0", "xxx"},
+			{"agent", "0.9.0", "ddd"},
+		},
+	})
+}
+
+func TestDiff(t *testing.T) {
+	client := newDiffServer(t).client()
+
+	diff, err := client.Diff(NewRepo("ecorp", "staging"), NewRepo("ecorp", "production"), SearchOptions{}, DiffOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, entry := range diff.Entries {
+		got = append(got, string(entry.Status)+" "+entry.Version+" "+entry.LeftChecksum+" "+entry.RightChecksum)
+	}
+	expected := []string{
+		"right-only 0.9.0-1  ",
+		"changed 1.1.0-1 sha256:bbb sha256:xxx",
+		"left-only 1.10.0-1  ",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if diff.Same != 1 {
+		t.Errorf("expected 1 identical package, got %d", diff.Same)
+	}
+}
+
+func TestDiffSkipChecksums(t *testing.T) {
+	client := newDiffServer(t).client()
+
+	diff, err := client.Diff(NewRepo("ecorp", "staging"), NewRepo("ecorp", "production"), SearchOptions{}, DiffOptions{SkipChecksums: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Entries) != 2 || diff.Same != 2 {
+		t.Errorf("expected 2 differences and 2 identical packages, got %+v", diff)
+	}
+}
@@ -3,9 +3,14 @@ package packagecloud
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 var (
+	ErrChecksumMismatch     = errors.New("package already exists in destination with a different checksum")
+	ErrDryRun               = errors.New("refusing to perform mutating request in dry-run mode")
 	ErrNotFound             = errors.New("not found -- wrong api token?")
 	ErrPackageAlreadyExists = errors.New("package already exists")
 	ErrPaymentRequired      = errors.New("payment required")
@@ -15,7 +20,25 @@ var (
 type MissingSearchOptionsError struct{}
 
 func (e *MissingSearchOptionsError) Error() string {
-	return "one or more of the query, filter, dist, and/or arch flags must be specified"
+	return "one or more of the query, filter, dist, and/or arch flags or a client-side filter must be specified"
+}
+
+type QuerySyntaxError struct {
+	Query string
+
+	// Pos is the byte offset in Query of the character the error is for.
+	Pos int
+
+	Msg string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	pos := e.Pos
+	if pos > len(e.Query) {
+		pos = len(e.Query)
+	}
+	column := utf8.RuneCountInString(e.Query[:pos])
+	return fmt.Sprintf("invalid query at position %d: %s\n  %s\n  %s^", column+1, e.Msg, e.Query, strings.Repeat(" ", column))
 }
 
 type UnmarshalError struct {
@@ -34,3 +57,136 @@ type MissingOptionError struct {
 func (e *MissingOptionError) Error() string {
 	return fmt.Sprintf("missing required option: %s", e.Field)
 }
+
+type WaitTimeoutError struct {
+	Timeout time.Duration
+	Pending []string
+}
+
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("packages have not finished indexing after %s: %s",
+		e.Timeout, strings.Join(e.Pending, ", "))
+}
+
+type PromoteFailedError struct {
+	Failures []PromoteResult
+	Total    int
+}
+
+func (e *PromoteFailedError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("failed to promote %s: %s", e.Failures[0].Package.Filename, e.Failures[0].Err)
+	}
+	return fmt.Sprintf("failed to promote %d of %d package(s)", len(e.Failures), e.Total)
+}
+
+func (e *PromoteFailedError) Unwrap() error {
+	return e.Failures[0].Err
+}
+
+type ChecksumError struct {
+	Filename  string
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch for %s: expected %s, got %s",
+		e.Algorithm, e.Filename, e.Expected, e.Actual)
+}
+
+type CopyFailedError struct {
+	Failures []CopyResult
+	Total    int
+}
+
+func (e *CopyFailedError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("failed to copy %s: %s", e.Failures[0].Package.Filename, e.Failures[0].Err)
+	}
+	return fmt.Sprintf("failed to copy %d of %d package(s)", len(e.Failures), e.Total)
+}
+
+func (e *CopyFailedError) Unwrap() error {
+	return e.Failures[0].Err
+}
+
+type VersionNotNewerError struct {
+	Candidate string
+	Latest    string
+}
+
+func (e *VersionNotNewerError) Error() string {
+	return fmt.Sprintf("version %s is not newer than the latest published version %s", e.Candidate, e.Latest)
+}
+
+type PruneFailedError struct {
+	Failures []PruneResult
+	Total    int
+}
+
+func (e *PruneFailedError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("failed to delete %s: %s", e.Failures[0].Package.Filename, e.Failures[0].Err)
+	}
+	return fmt.Sprintf("failed to delete %d of %d package(s)", len(e.Failures), e.Total)
+}
+
+func (e *PruneFailedError) Unwrap() error {
+	return e.Failures[0].Err
+}
+
+type PruneLimitError struct {
+	Planned int
+	Max     int
+}
+
+func (e *PruneLimitError) Error() string {
+	return fmt.Sprintf("refusing to delete %d package(s), more than the maximum of %d", e.Planned, e.Max)
+}
+
+type SyncFailedError struct {
+	Failures []SyncResult
+	Total    int
+}
+
+func (e *SyncFailedError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("failed to %s %s: %s", e.Failures[0].Action, e.Failures[0].Package.Filename, e.Failures[0].Err)
+	}
+	return fmt.Sprintf("failed to sync %d of %d package(s)", len(e.Failures), e.Total)
+}
+
+func (e *SyncFailedError) Unwrap() error {
+	return e.Failures[0].Err
+}
+
+type RestoreFailedError struct {
+	Failures []RestoreResult
+	Total    int
+}
+
+func (e *RestoreFailedError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("failed to restore %s: %s", e.Failures[0].Package.Filename, e.Failures[0].Err)
+	}
+	return fmt.Sprintf("failed to restore %d of %d package(s)", len(e.Failures), e.Total)
+}
+
+func (e *RestoreFailedError) Unwrap() error {
+	return e.Failures[0].Err
+}
+
+type WatchEventError struct {
+	Event WatchEvent
+	Err   error
+}
+
+func (e *WatchEventError) Error() string {
+	return fmt.Sprintf("failed to handle %s event for %s: %s", e.Event.Type, e.Event.Package.Filename, e.Err)
+}
+
+func (e *WatchEventError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,190 @@
+package packagecloud
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// FragmentFilter reports whether or not a package should be included in
+// search results. Filters are applied client-side, after the server has
+// returned the packages matching the rest of the SearchOptions.
+type FragmentFilter func(pkg types.PackageFragment) bool
+
+// AllFilters returns a filter that matches packages matched by every one of
+// filters.
+func AllFilters(filters ...FragmentFilter) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		for _, filter := range filters {
+			if !filter(pkg) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyFilter returns a filter that matches packages matched by at least one
+// of filters.
+func AnyFilter(filters ...FragmentFilter) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		for _, filter := range filters {
+			if filter(pkg) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NotFilter returns a filter that matches packages not matched by filter.
+func NotFilter(filter FragmentFilter) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return !filter(pkg)
+	}
+}
+
+// NameFilter matches packages with exactly the given name.
+func NameFilter(name string) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return pkg.Name == name
+	}
+}
+
+// NameRegexpFilter matches packages whose name matches re.
+func NameRegexpFilter(re *regexp.Regexp) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return re.MatchString(pkg.Name)
+	}
+}
+
+// FilenameFilter matches packages whose filename contains substr.
+func FilenameFilter(substr string) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return strings.Contains(pkg.Filename, substr)
+	}
+}
+
+// ArchFilter matches packages whose architecture matches pattern, using the
+// syntax of path.Match (i.e. amd64, arm*).
+func ArchFilter(pattern string) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		ok, _ := path.Match(pattern, pkg.Architecture)
+		return ok
+	}
+}
+
+// DistroFilter matches packages whose distro version matches pattern, using
+// the syntax of path.Match (i.e. ubuntu/jammy, ubuntu/*, el/[89]). A pattern
+// without a slash is matched against the distro name only (i.e. ubuntu).
+func DistroFilter(pattern string) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		distro := pkg.DistroVersion
+		if !strings.Contains(pattern, "/") {
+			distro, _, _ = strings.Cut(distro, "/")
+		}
+		ok, _ := path.Match(pattern, distro)
+		return ok
+	}
+}
+
+// TypeFilter matches packages of the given type (i.e. deb, rpm, gem).
+func TypeFilter(packageType string) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return pkg.Type == packageType
+	}
+}
+
+// VersionFilter matches packages whose version key (see
+// types.PackageVersionKey) satisfies constraint. Versions are compared with
+// scheme or, if scheme is nil, the scheme of each package's type. Packages
+// with versions that cannot be parsed never match.
+func VersionFilter(constraint *types.VersionConstraint, scheme types.VersionScheme) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		s := scheme
+		if s == nil {
+			s = types.VersionSchemeForPackageType(pkg.Type)
+		}
+
+		ok, err := constraint.Check(s, types.PackageVersionKey(s, pkg.Epoch, pkg.Version, pkg.Release))
+		return err == nil && ok
+	}
+}
+
+// UploadedAfterFilter matches packages uploaded after t. Packages with an
+// invalid upload time never match.
+func UploadedAfterFilter(t time.Time) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		created, err := pkg.CreatedTime()
+		return err == nil && created.After(t)
+	}
+}
+
+// UploadedBeforeFilter matches packages uploaded before t. Packages with an
+// invalid upload time never match.
+func UploadedBeforeFilter(t time.Time) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		created, err := pkg.CreatedTime()
+		return err == nil && created.Before(t)
+	}
+}
+
+// UploaderFilter matches packages uploaded by the user with the given name.
+func UploaderFilter(name string) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return pkg.UploaderName == name
+	}
+}
+
+// IndexedFilter matches packages that have (or have not) been indexed.
+func IndexedFilter(indexed bool) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return pkg.Indexed == indexed
+	}
+}
+
+// PrivateFilter matches packages that are (or are not) in a private
+// repository.
+func PrivateFilter(private bool) FragmentFilter {
+	return func(pkg types.PackageFragment) bool {
+		return pkg.Private == private
+	}
+}
+
+var relativeTimePattern = regexp.MustCompile(`^(\d+)([smhdw])$`)
+
+// ParseUploadTime parses a point in time for the upload filters. It accepts
+// RFC 3339 timestamps (2024-01-02T15:04:05Z), dates (2024-01-02, as
+// midnight UTC) and ages relative to now (i.e. 30d, 12h, 2w).
+func ParseUploadTime(s string, now time.Time) (time.Time, error) {
+	if match := relativeTimePattern.FindStringSubmatch(s); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q: %w", s, err)
+		}
+
+		unit := map[string]time.Duration{
+			"s": time.Second,
+			"m": time.Minute,
+			"h": time.Hour,
+			"d": 24 * time.Hour,
+			"w": 7 * 24 * time.Hour,
+		}[match[2]]
+
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a date (2006-01-02), an RFC 3339 timestamp or an age (i.e. 30d, 12h)", s)
+}
@@ -0,0 +1,120 @@
+package packagecloud
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+func TestFilters(t *testing.T) {
+	pkg := types.PackageFragment{
+		Name:         "ecorp-agent",
+		Version:      "3.2.1",
+		Release:      "1",
+		Type:         "deb",
+		CreatedAt:    "2024-03-01T12:00:00.000Z",
+		UploaderName: "alice",
+		Indexed:      true,
+	}
+
+	constraint, err := types.ParseVersionConstraint(">=3.2, <4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tooNew, err := types.ParseVersionConstraint(">=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   FragmentFilter
+		expected bool
+	}{
+		{"name", NameFilter("ecorp-agent"), true},
+		{"name prefix", NameFilter("ecorp"), false},
+		{"name regexp", NameRegexpFilter(regexp.MustCompile(`^ecorp-`)), true},
+		{"name regexp mismatch", NameRegexpFilter(regexp.MustCompile(`-cli$`)), false},
+		{"version", VersionFilter(constraint, nil), true},
+		{"version mismatch", VersionFilter(tooNew, nil), false},
+		{"uploaded after", UploadedAfterFilter(march), true},
+		{"uploaded before", UploadedBeforeFilter(march), false},
+		{"uploader", UploaderFilter("alice"), true},
+		{"uploader mismatch", UploaderFilter("bob"), false},
+		{"indexed", IndexedFilter(true), true},
+		{"private", PrivateFilter(true), false},
+		{"all", AllFilters(NameFilter("ecorp-agent"), IndexedFilter(true)), true},
+		{"all mismatch", AllFilters(NameFilter("ecorp-agent"), PrivateFilter(true)), false},
+		{"any", AnyFilter(NameFilter("other"), IndexedFilter(true)), true},
+		{"any mismatch", AnyFilter(NameFilter("other"), PrivateFilter(true)), false},
+		{"not", NotFilter(PrivateFilter(true)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter(pkg); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseUploadTime(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-02T15:04:05Z", time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"30d", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{"12h", time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2024, 3, 17, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseUploadTime(tt.input, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := ParseUploadTime("last week", now); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestSearchAppliesFilters(t *testing.T) {
+	server := newFakeServer(t, map[string]types.PackageFragments{"user/repo": {
+		{Name: "a", Version: "1.0.0", Type: "deb"},
+		{Name: "a", Version: "2.0.0", Type: "deb"},
+		{Name: "b", Version: "2.0.0", Type: "deb"},
+	}})
+	client := server.client()
+
+	constraint, err := types.ParseVersionConstraint(">=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packages, err := client.Search(SearchOptions{
+		RepoUser: "user",
+		RepoName: "repo",
+		Filters:  []FragmentFilter{NameFilter("a"), VersionFilter(constraint, nil)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 1 || packages[0].Name != "a" || packages[0].Version != "2.0.0" {
+		t.Errorf("expected only a 2.0.0, got %+v", packages)
+	}
+}
@@ -2,11 +2,18 @@ package packagecloud
 
 import (
 	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"mime/multipart"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -102,6 +109,130 @@ func (c *Client) PushPackage(options PushPackageOptions) (*types.PackageDetails,
 	return &pkg, nil
 }
 
+// GetPackageDetails retrieves the details of a package, including its
+// checksums, from the package URL of the given package fragment.
+func (c *Client) GetPackageDetails(pkg types.PackageFragment) (*types.PackageDetails, error) {
+	if isEmptyString(pkg.PackageURL) {
+		return nil, fmt.Errorf("package %s has no package url", pkg.Filename)
+	}
+
+	packageURL, err := url.Parse(pkg.PackageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse package url: %s", err)
+	}
+
+	endpoint := c.getURL(packageURL)
+
+	resp, err := c.apiRequest("GET", endpoint.String(), nil, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	var details types.PackageDetails
+	if err := json.Unmarshal(resp.Body, &details); err != nil {
+		return nil, &UnmarshalError{
+			Data: resp.Body,
+			Err:  err,
+		}
+	}
+
+	return &details, nil
+}
+
+// DownloadPackage downloads the package file described by details and writes
+// it to w. The contents are verified against the strongest checksum
+// available in details; a *ChecksumError is returned if they do not match.
+func (c *Client) DownloadPackage(details types.PackageDetails, w io.Writer) error {
+	if isEmptyString(details.DownloadURL) {
+		return fmt.Errorf("package %s has no download url", details.Filename)
+	}
+
+	algorithm, expected := details.Checksum()
+	var h hash.Hash
+	switch algorithm {
+	case "sha512":
+		h = sha512.New()
+	case "sha256":
+		h = sha256.New()
+	case "sha1":
+		h = sha1.New()
+	case "md5":
+		h = md5.New()
+	default:
+		return fmt.Errorf("package %s has no checksum to verify the download against", details.Filename)
+	}
+
+	downloadURL, err := url.Parse(details.DownloadURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse download url: %s", err)
+	}
+
+	req, err := http.NewRequest("GET", c.getURL(downloadURL).String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %s", err)
+	}
+	req.SetBasicAuth(c.config.Token, "")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == 401:
+		return ErrUnauthenticated
+	case resp.StatusCode == 404:
+		return ErrNotFound
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("download responded with status: %s", resp.Status)
+	}
+
+	if _, err := io.Copy(io.MultiWriter(w, h), resp.Body); err != nil {
+		return fmt.Errorf("failed to download package: %w", err)
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return &ChecksumError{
+			Filename:  details.Filename,
+			Algorithm: algorithm,
+			Expected:  expected,
+			Actual:    actual,
+		}
+	}
+
+	return nil
+}
+
+// FindPackage searches repo for a package with the given filename in the
+// given distro. If no such package exists, nil is returned.
+func (c *Client) FindPackage(repo Repo, distro Distro, filename string) (*types.PackageFragment, error) {
+	if err := repo.Validate(); err != nil {
+		return nil, fmt.Errorf("repository validation failed: %w", err)
+	}
+	if err := distro.Validate(); err != nil {
+		return nil, fmt.Errorf("distro validation failed: %w", err)
+	}
+
+	packages, err := c.Search(SearchOptions{
+		RepoUser: repo.User,
+		RepoName: repo.Name,
+		Query:    filename,
+		Dist:     distro.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range packages {
+		if pkg.Filename == filename && pkg.DistroVersion == distro.String() {
+			return &pkg, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (c *Client) ListPackages(repo Repo) (types.PackageFragments, error) {
 	var packages types.PackageFragments
 	mu := &sync.RWMutex{}
@@ -1,6 +1,7 @@
 package packagecloud
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -13,82 +14,282 @@ const (
 	promotePath = "/api/v1/repos/%s/%s/%s/promote.json"
 )
 
+type PromoteStatus string
+
+const (
+	// PromoteStatusPromoted indicates that the package was promoted.
+	PromoteStatusPromoted PromoteStatus = "promoted"
+
+	// PromoteStatusPlanned indicates that the package would have been
+	// promoted if the client was not in dry-run mode.
+	PromoteStatusPlanned PromoteStatus = "planned"
+
+	// PromoteStatusFailed indicates that promoting the package failed.
+	PromoteStatusFailed PromoteStatus = "failed"
+
+	// PromoteStatusExists indicates that the package was not promoted
+	// because an identical package already exists in the destination
+	// repository.
+	PromoteStatusExists PromoteStatus = "exists"
+
+	// PromoteStatusSkipped indicates that promoting the package was not
+	// attempted because an earlier promotion failed.
+	PromoteStatusSkipped PromoteStatus = "skipped"
+)
+
+// PromoteResult describes the outcome of promoting a single package.
+type PromoteResult struct {
+	// Source is the repository the package was promoted from.
+	Source Repo
+
+	// Destination is the repository the package was promoted to.
+	Destination Repo
+
+	// Package is the package that was promoted.
+	Package types.PackageFragment
+
+	// Status is the outcome of the promotion.
+	Status PromoteStatus
+
+	// Exists specifies whether or not the package already exists in the
+	// destination repository.
+	Exists bool
+
+	// Err is the error that caused the promotion to fail, if any.
+	Err error
+}
+
+func (r PromoteResult) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Source      string                `json:"source"`
+		Destination string                `json:"destination"`
+		Package     types.PackageFragment `json:"package"`
+		Status      PromoteStatus         `json:"status"`
+		Exists      bool                  `json:"exists"`
+		Error       string                `json:"error,omitempty"`
+	}{
+		Source:      r.Source.String(),
+		Destination: r.Destination.String(),
+		Package:     r.Package,
+		Status:      r.Status,
+		Exists:      r.Exists,
+		Error:       errMsg,
+	})
+}
+
+// PromoteObserver can be used to follow the progress of a promotion. When
+// promoting concurrently, its methods may be called from multiple goroutines
+// at the same time.
+type PromoteObserver interface {
+	// PromoteStarted is called before a package is promoted.
+	PromoteStarted(src Repo, dst Repo, pkg types.PackageFragment)
+
+	// PromoteFinished is called after a package has been promoted, or has
+	// failed to be promoted.
+	PromoteFinished(result PromoteResult)
+}
+
+type PromoteOptions struct {
+	// Observer, if set, is notified before and after each package is
+	// promoted.
+	Observer PromoteObserver
+
+	// Concurrency is the maximum number of packages to promote at the same
+	// time. Defaults to 1.
+	Concurrency int
+
+	// ContinueOnError continues promoting the remaining packages after a
+	// promotion fails. When false, packages that have not been promoted
+	// by the time a promotion fails are skipped.
+	ContinueOnError bool
+}
+
 // PromoteByFilename will promote a single package by filename.
-func (c *Client) PromoteByFilename(src Repo, dst Repo, distro Distro, filename string) error {
+func (c *Client) PromoteByFilename(src Repo, dst Repo, distro Distro, filename string, opts PromoteOptions) (PromoteResult, error) {
 	if err := src.Validate(); err != nil {
-		return fmt.Errorf("source repository validation failed: %w", err)
+		return PromoteResult{}, fmt.Errorf("source repository validation failed: %w", err)
 	}
 	if err := dst.Validate(); err != nil {
-		return fmt.Errorf("destination repository validation failed: %w", err)
+		return PromoteResult{}, fmt.Errorf("destination repository validation failed: %w", err)
 	}
 	if err := distro.Validate(); err != nil {
-		return fmt.Errorf("distro validation failed: %w", err)
+		return PromoteResult{}, fmt.Errorf("distro validation failed: %w", err)
 	}
 	if isEmptyString(filename) {
-		return errors.New("filename cannot be empty")
+		return PromoteResult{}, errors.New("filename cannot be empty")
 	}
 
-	pkg := types.PackageFragment{
-		DistroVersion: distro.String(),
-		Filename:      filename,
+	found, err := c.FindPackage(src, distro, filename)
+	if err != nil {
+		return PromoteResult{}, fmt.Errorf("failed to search source repository: %w", err)
+	}
+	if found == nil {
+		return PromoteResult{}, fmt.Errorf("package %s was not found in %s for distro %s", filename, src, distro)
 	}
 
+	pkg := *found
 	pkg.PromoteURL = buildPromoteURL(src, pkg)
 
-	fmt.Println("Promoting package")
-	fmt.Printf("  - Source repository:      %s\n", src)
-	fmt.Printf("  - Destination repository: %s\n", dst)
-	fmt.Printf("  - Filename:               %s\n", pkg.Filename)
-	fmt.Printf("  - Distro:                 %s\n", pkg.DistroVersion)
-	fmt.Println("")
-
-	if err := c.promote(pkg, dst); err != nil {
-		return err
+	results, err := c.PromotePackages(src, dst, types.PackageFragments{pkg}, opts)
+	if len(results) == 0 {
+		return PromoteResult{}, err
 	}
-
-	fmt.Println("Successfully promoted 1 package")
-
-	return nil
+	return results[0], err
 }
 
 // PromoteBySearch will search for any packages matching the given search
 // options and then promote all matches to the destination repository.
-func (c *Client) PromoteBySearch(dst Repo, options SearchOptions) error {
+func (c *Client) PromoteBySearch(dst Repo, options SearchOptions, opts PromoteOptions) ([]PromoteResult, error) {
 	if err := dst.Validate(); err != nil {
-		return fmt.Errorf("destination repository validation failed: %w", err)
+		return nil, fmt.Errorf("destination repository validation failed: %w", err)
 	}
 	if err := options.Validate(); err != nil {
-		return fmt.Errorf("search options validation failed: %w", err)
+		return nil, fmt.Errorf("search options validation failed: %w", err)
 	}
 
 	src := NewRepo(options.RepoUser, options.RepoName)
 
 	packages, err := c.Search(options)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, pkg := range packages {
-		fmt.Println("Promoting package")
-		fmt.Printf("  - Source repository:      %s\n", src)
-		fmt.Printf("  - Destination repository: %s\n", dst)
-		fmt.Printf("  - Name:                   %s\n", pkg.Name)
-		fmt.Printf("  - Type:                   %s\n", pkg.Type)
-		fmt.Printf("  - Version:                %s\n", pkg.Version)
-		fmt.Printf("  - Release:                %s\n", pkg.Release)
-		fmt.Printf("  - Epoch:                  %d\n", pkg.Epoch)
-		fmt.Printf("  - Architecture:           %s\n", pkg.Architecture)
-		fmt.Printf("  - Distro:                 %s\n", pkg.DistroVersion)
-		fmt.Println("")
-
-		if err := c.promote(pkg, dst); err != nil {
-			return err
+	return c.PromotePackages(src, dst, packages, opts)
+}
+
+// PromotePackages will promote each of the given packages from the source
+// repository to the destination repository. A result is returned for every
+// package, in the same order as packages. If any promotion fails, a
+// *PromoteFailedError is returned along with the results.
+func (c *Client) PromotePackages(src Repo, dst Repo, packages types.PackageFragments, opts PromoteOptions) ([]PromoteResult, error) {
+	if err := src.Validate(); err != nil {
+		return nil, fmt.Errorf("source repository validation failed: %w", err)
+	}
+	if err := dst.Validate(); err != nil {
+		return nil, fmt.Errorf("destination repository validation failed: %w", err)
+	}
+
+	results := make([]PromoteResult, len(packages))
+
+	forEach(len(packages), opts.Concurrency, opts.ContinueOnError, func(i int) bool {
+		pkg := packages[i]
+
+		if opts.Observer != nil {
+			opts.Observer.PromoteStarted(src, dst, pkg)
+		}
+
+		results[i] = c.promotePackage(src, dst, pkg)
+
+		if opts.Observer != nil {
+			opts.Observer.PromoteFinished(results[i])
+		}
+
+		return results[i].Err == nil
+	}, func(i int) {
+		results[i] = PromoteResult{
+			Source:      src,
+			Destination: dst,
+			Package:     packages[i],
+			Status:      PromoteStatusSkipped,
+		}
+	})
+
+	var failures []PromoteResult
+	for _, result := range results {
+		if result.Status == PromoteStatusFailed {
+			failures = append(failures, result)
+		}
+	}
+	if len(failures) > 0 {
+		return results, &PromoteFailedError{
+			Failures: failures,
+			Total:    len(results),
 		}
 	}
 
-	fmt.Printf("Successfully promoted %d package(s)\n", len(packages))
+	return results, nil
+}
 
-	return nil
+// promotePackage promotes a single package unless it already exists in the
+// destination repository. An existing package is skipped if its checksum
+// matches the source package, otherwise the promotion fails with
+// ErrChecksumMismatch. In dry-run mode, everything is resolved but nothing
+// is promoted.
+func (c *Client) promotePackage(src Repo, dst Repo, pkg types.PackageFragment) PromoteResult {
+	result := PromoteResult{
+		Source:      src,
+		Destination: dst,
+		Package:     pkg,
+	}
+
+	fail := func(err error) PromoteResult {
+		result.Status = PromoteStatusFailed
+		result.Err = err
+		return result
+	}
+
+	distro, err := NewDistroFromString(pkg.DistroVersion)
+	if err != nil {
+		return fail(fmt.Errorf("invalid distro: %w", err))
+	}
+
+	existing, err := c.FindPackage(dst, distro, pkg.Filename)
+	if err != nil {
+		return fail(fmt.Errorf("failed to search destination repository: %w", err))
+	}
+
+	if existing != nil {
+		result.Exists = true
+
+		same, err := c.sameChecksum(pkg, *existing)
+		if err != nil {
+			return fail(err)
+		}
+		if !same {
+			return fail(ErrChecksumMismatch)
+		}
+
+		result.Status = PromoteStatusExists
+		return result
+	}
+
+	if c.DryRun() {
+		result.Status = PromoteStatusPlanned
+		return result
+	}
+
+	if err := c.promote(pkg, dst); err != nil {
+		return fail(err)
+	}
+
+	result.Status = PromoteStatusPromoted
+	return result
+}
+
+// sameChecksum retrieves the details of both packages and compares their
+// checksums.
+func (c *Client) sameChecksum(a types.PackageFragment, b types.PackageFragment) (bool, error) {
+	aDetails, err := c.GetPackageDetails(a)
+	if err != nil {
+		return false, fmt.Errorf("failed to get package details: %w", err)
+	}
+
+	bDetails, err := c.GetPackageDetails(b)
+	if err != nil {
+		return false, fmt.Errorf("failed to get package details: %w", err)
+	}
+
+	same, err := aDetails.SameChecksum(*bDetails)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare checksums: %w", err)
+	}
+
+	return same, nil
 }
 
 func (c *Client) promote(pkg types.PackageFragment, dst Repo) error {
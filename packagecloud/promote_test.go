@@ -0,0 +1,113 @@
+package packagecloud
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// newPromoteServer returns a fake server with the packages of user/staging
+// with the given names, where promoting the ones named "bad..." fails. The
+// production repository already has the packages "same", with the same
+// checksum as in user/staging, and "changed", with a different checksum.
+func newPromoteServer(t *testing.T, names ...string) (*fakeServer, types.PackageFragments) {
+	t.Helper()
+
+	var staging types.PackageFragments
+	for _, name := range names {
+		staging = append(staging, fakePackage("user/staging", "ubuntu/jammy", "amd64", name, "1.0"))
+	}
+	production := types.PackageFragments{
+		fakePackage("user/production", "ubuntu/jammy", "amd64", "same", "1.0"),
+		fakePackage("user/production", "ubuntu/jammy", "amd64", "changed", "1.0"),
+	}
+
+	server := newFakeServer(t, map[string]types.PackageFragments{
+		"user/staging":    staging,
+		"user/production": production,
+	})
+	for _, pkg := range staging {
+		server.addDetails(pkg, types.PackageDetails{SHA256Sum: "original"})
+		if strings.HasPrefix(pkg.Name, "bad") {
+			server.fail(pkg.PromoteURL)
+		}
+	}
+	server.addDetails(production[0], types.PackageDetails{SHA256Sum: "original"})
+	server.addDetails(production[1], types.PackageDetails{SHA256Sum: "changed"})
+
+	return server, staging
+}
+
+func TestPromotePackagesStopsAtFirstFailure(t *testing.T) {
+	server, packages := newPromoteServer(t, "a", "bad", "c")
+	client := server.client()
+
+	results, err := client.PromotePackages(NewRepo("user", "staging"), NewRepo("user", "production"), packages, PromoteOptions{})
+
+	var promoteErr *PromoteFailedError
+	if !errors.As(err, &promoteErr) {
+		t.Fatalf("expected PromoteFailedError, got %v", err)
+	}
+
+	expected := []PromoteStatus{PromoteStatusPromoted, PromoteStatusFailed, PromoteStatusSkipped}
+	for i, result := range results {
+		if result.Status != expected[i] {
+			t.Errorf("expected %s to be %s, got %s", result.Package.Filename, expected[i], result.Status)
+		}
+	}
+}
+
+func TestPromotePackagesContinueOnError(t *testing.T) {
+	server, packages := newPromoteServer(t, "a", "bad", "c", "d", "bad2")
+	client := server.client()
+
+	results, err := client.PromotePackages(NewRepo("user", "staging"), NewRepo("user", "production"), packages, PromoteOptions{
+		Concurrency:     3,
+		ContinueOnError: true,
+	})
+
+	var promoteErr *PromoteFailedError
+	if !errors.As(err, &promoteErr) {
+		t.Fatalf("expected PromoteFailedError, got %v", err)
+	}
+	if len(promoteErr.Failures) != 2 {
+		t.Errorf("expected 2 failures, got %d", len(promoteErr.Failures))
+	}
+
+	for i, result := range results {
+		if result.Package.Filename != packages[i].Filename {
+			t.Errorf("expected results in input order, got %s at %d", result.Package.Filename, i)
+		}
+		failed := strings.HasPrefix(result.Package.Filename, "bad")
+		if failed && result.Status != PromoteStatusFailed {
+			t.Errorf("expected %s to fail, got %s", result.Package.Filename, result.Status)
+		}
+		if !failed && result.Status != PromoteStatusPromoted {
+			t.Errorf("expected %s to be promoted, got %s", result.Package.Filename, result.Status)
+		}
+	}
+}
+
+func TestPromotePackagesSkipsExisting(t *testing.T) {
+	server, packages := newPromoteServer(t, "same", "changed", "new")
+	client := server.client()
+
+	results, err := client.PromotePackages(NewRepo("user", "staging"), NewRepo("user", "production"), packages, PromoteOptions{
+		ContinueOnError: true,
+	})
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+
+	expected := []PromoteStatus{PromoteStatusExists, PromoteStatusFailed, PromoteStatusPromoted}
+	for i, result := range results {
+		if result.Status != expected[i] {
+			t.Errorf("expected %s to be %s, got %s", result.Package.Filename, expected[i], result.Status)
+		}
+	}
+	if !results[0].Exists || !results[1].Exists || results[2].Exists {
+		t.Errorf("expected only same and changed to exist in destination")
+	}
+}
@@ -0,0 +1,413 @@
+package packagecloud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+const (
+	defaultPruneMaxDeletions = 50
+)
+
+type PruneStatus string
+
+const (
+	// PruneStatusDeleted indicates that the package was deleted.
+	PruneStatusDeleted PruneStatus = "deleted"
+
+	// PruneStatusPlanned indicates that the package would have been deleted
+	// if the client was not in dry-run mode.
+	PruneStatusPlanned PruneStatus = "planned"
+
+	// PruneStatusFailed indicates that deleting the package failed.
+	PruneStatusFailed PruneStatus = "failed"
+
+	// PruneStatusSkipped indicates that the package was not deleted because
+	// an earlier deletion failed.
+	PruneStatusSkipped PruneStatus = "skipped"
+)
+
+type PruneOptions struct {
+	// Repo is the repository to prune.
+	Repo Repo
+
+	// Keep is the number of newest versions to keep in each group of
+	// packages. Must be at least 1.
+	Keep int
+
+	// PerDistro keeps Keep versions for each distro version, rather than
+	// across all of them.
+	PerDistro bool
+
+	// PerArch keeps Keep versions for each architecture, rather than across
+	// all of them.
+	PerArch bool
+
+	// OlderThan, if set, only deletes packages uploaded before it, even if
+	// they are not within the newest Keep versions.
+	OlderThan time.Time
+
+	// PackageName, if set, restricts the prune to packages with this name.
+	PackageName string
+
+	// Filter can be used to restrict the prune to a package type.
+	// (RPMs, Debs, DSCs, Gem, Python, Node). Ignored when Dist is present.
+	Filter string
+
+	// Dist restricts the prune to a distribution (i.e. ubuntu, el/6).
+	Dist string
+
+	// Arch restricts the prune to an architecture. Alpine/RPM/Debian only.
+	Arch string
+
+	// Query, if set, restricts the prune to the packages matching it (see
+	// ParseQuery).
+	Query *Query
+
+	// Protected lists versions that are never deleted. Each one is matched
+	// against both the version and the version key (i.e.
+	// [epoch:]version[-release]) of a package, with the syntax of
+	// path.Match (i.e. 1.4.3, 2.0.*).
+	Protected []string
+
+	// Scheme is the version scheme used to order versions. When nil, the
+	// scheme is chosen from the type of the packages.
+	Scheme types.VersionScheme
+}
+
+func (o PruneOptions) Validate() error {
+	if err := o.Repo.Validate(); err != nil {
+		return fmt.Errorf("repository validation failed: %w", err)
+	}
+	if o.Keep < 1 {
+		return errors.New("the number of versions to keep must be at least 1")
+	}
+	for _, pattern := range o.Protected {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid protected version %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (o PruneOptions) searchOptions() SearchOptions {
+	options := SearchOptions{
+		RepoUser: o.Repo.User,
+		RepoName: o.Repo.Name,
+		Filter:   o.Filter,
+		Dist:     o.Dist,
+		Arch:     o.Arch,
+	}
+	if o.PackageName != "" {
+		options.Filters = []FragmentFilter{NameFilter(o.PackageName)}
+	}
+	if o.Query != nil {
+		o.Query.Apply(&options)
+	}
+	return options
+}
+
+// PruneKeepReason describes why a package is kept by a prune.
+type PruneKeepReason string
+
+const (
+	// PruneKeepNewest indicates that the package is one of the newest
+	// versions of its group.
+	PruneKeepNewest PruneKeepReason = "newest"
+
+	// PruneKeepProtected indicates that the version of the package is
+	// protected.
+	PruneKeepProtected PruneKeepReason = "protected"
+
+	// PruneKeepRecent indicates that the package was uploaded after
+	// PruneOptions.OlderThan.
+	PruneKeepRecent PruneKeepReason = "recent"
+
+	// PruneKeepInvalidVersion indicates that the version of the package
+	// could not be parsed, so it can't be ranked.
+	PruneKeepInvalidVersion PruneKeepReason = "invalid version"
+)
+
+// PrunePlan is the outcome of planning a prune.
+type PrunePlan struct {
+	Repo Repo
+
+	// Delete are the packages that would be deleted, ordered by group and
+	// from newest to oldest version.
+	Delete types.PackageFragments
+
+	// Kept maps the reason packages are kept to the number of packages kept
+	// for that reason.
+	Kept map[PruneKeepReason]int
+
+	// Groups is the number of groups packages were ranked in.
+	Groups int
+}
+
+// pruneGroup returns the key of the group a package is ranked in.
+func pruneGroup(pkg types.PackageFragment, options PruneOptions) string {
+	parts := []string{pkg.Name, pkg.Type}
+	if options.PerDistro {
+		parts = append(parts, pkg.DistroVersion)
+	}
+	if options.PerArch {
+		parts = append(parts, pkg.Architecture)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// PlanPrune returns the packages of the repository that are not among the
+// options.Keep newest versions of their group. Packages are grouped by name
+// and type, and, depending on options, distro version and architecture.
+// Packages of the same version count as a single version. Nothing is
+// deleted.
+func (c *Client) PlanPrune(options PruneOptions) (*PrunePlan, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	packages, err := c.findPackages(options.searchOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages: %w", err)
+	}
+
+	return planPrune(options, packages), nil
+}
+
+func planPrune(options PruneOptions, packages types.PackageFragments) *PrunePlan {
+	plan := &PrunePlan{
+		Repo:   options.Repo,
+		Delete: types.PackageFragments{},
+		Kept:   map[PruneKeepReason]int{},
+	}
+
+	groups := map[string]types.PackageFragments{}
+	var keys []string
+	for _, pkg := range packages {
+		key := pruneGroup(pkg, options)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], pkg)
+	}
+	sort.Strings(keys)
+	plan.Groups = len(keys)
+
+	for _, key := range keys {
+		group := groups[key]
+		scheme := options.Scheme
+		if scheme == nil {
+			scheme = types.VersionSchemeForPackageType(group[0].Type)
+		}
+
+		// rank the distinct valid versions of the group, newest first
+		var versions []string
+		ranked := map[string]bool{}
+		for _, pkg := range group {
+			version := pruneVersionKey(scheme, pkg)
+			if ranked[version] {
+				continue
+			}
+			if _, err := scheme.Compare(version, version); err != nil {
+				continue
+			}
+			ranked[version] = true
+			versions = append(versions, version)
+		}
+		types.SortVersions(scheme, versions)
+
+		rank := map[string]int{}
+		for i, version := range versions {
+			rank[version] = i
+		}
+
+		sort.SliceStable(group, func(i, j int) bool {
+			return rank[pruneVersionKey(scheme, group[i])] < rank[pruneVersionKey(scheme, group[j])]
+		})
+
+		for _, pkg := range group {
+			version := pruneVersionKey(scheme, pkg)
+			switch {
+			case !ranked[version]:
+				plan.Kept[PruneKeepInvalidVersion]++
+			case rank[version] < options.Keep:
+				plan.Kept[PruneKeepNewest]++
+			case isProtectedVersion(scheme, pkg, options.Protected):
+				plan.Kept[PruneKeepProtected]++
+			case !uploadedBefore(pkg, options.OlderThan):
+				plan.Kept[PruneKeepRecent]++
+			default:
+				plan.Delete = append(plan.Delete, pkg)
+			}
+		}
+	}
+
+	return plan
+}
+
+// pruneVersionKey returns the version key of a package for scheme (see
+// types.PackageVersionKey).
+func pruneVersionKey(scheme types.VersionScheme, pkg types.PackageFragment) string {
+	return types.PackageVersionKey(scheme, pkg.Epoch, pkg.Version, pkg.Release)
+}
+
+func isProtectedVersion(scheme types.VersionScheme, pkg types.PackageFragment, protected []string) bool {
+	for _, pattern := range protected {
+		if ok, _ := path.Match(pattern, pkg.Version); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, pruneVersionKey(scheme, pkg)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadedBefore returns whether or not pkg was uploaded before t. Every
+// package is uploaded before the zero time, and packages with an invalid
+// upload time never are.
+func uploadedBefore(pkg types.PackageFragment, t time.Time) bool {
+	if t.IsZero() {
+		return true
+	}
+	created, err := pkg.CreatedTime()
+	return err == nil && created.Before(t)
+}
+
+// PruneResult describes the outcome of deleting a single package.
+type PruneResult struct {
+	// Package is the package that was deleted.
+	Package types.PackageFragment
+
+	// Status is the outcome of the deletion.
+	Status PruneStatus
+
+	// Err is the error that caused the deletion to fail, if any.
+	Err error
+}
+
+func (r PruneResult) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Package types.PackageFragment `json:"package"`
+		Status  PruneStatus           `json:"status"`
+		Error   string                `json:"error,omitempty"`
+	}{
+		Package: r.Package,
+		Status:  r.Status,
+		Error:   errMsg,
+	})
+}
+
+type PruneExecuteOptions struct {
+	// MaxDeletions is the maximum number of packages a prune may delete. A
+	// plan deleting more packages is refused as a whole. Defaults to 50.
+	MaxDeletions int
+
+	// Concurrency is the maximum number of packages to delete at the same
+	// time. Defaults to 1.
+	Concurrency int
+
+	// ContinueOnError continues deleting the remaining packages after a
+	// deletion fails. When false, packages that have not been deleted by
+	// the time a deletion fails are skipped.
+	ContinueOnError bool
+
+	// OnResult, if set, is called after each package has been deleted, or
+	// has failed to be deleted. It may be called from multiple goroutines
+	// at the same time.
+	OnResult func(PruneResult)
+}
+
+// ExecutePrune deletes the packages of plan. A result is returned for every
+// package, in the same order as plan.Delete. In dry-run mode, nothing is
+// deleted. If any deletion fails, a *PruneFailedError is returned along with
+// the results.
+func (c *Client) ExecutePrune(plan *PrunePlan, opts PruneExecuteOptions) ([]PruneResult, error) {
+	if opts.MaxDeletions <= 0 {
+		opts.MaxDeletions = defaultPruneMaxDeletions
+	}
+	if len(plan.Delete) > opts.MaxDeletions {
+		return nil, &PruneLimitError{
+			Planned: len(plan.Delete),
+			Max:     opts.MaxDeletions,
+		}
+	}
+
+	results := make([]PruneResult, len(plan.Delete))
+
+	forEach(len(plan.Delete), opts.Concurrency, opts.ContinueOnError, func(i int) bool {
+		pkg := plan.Delete[i]
+		results[i] = PruneResult{Package: pkg}
+
+		switch {
+		case c.DryRun():
+			results[i].Status = PruneStatusPlanned
+		default:
+			if err := c.DestroyPackage(pkg); err != nil {
+				results[i].Status = PruneStatusFailed
+				results[i].Err = err
+			} else {
+				results[i].Status = PruneStatusDeleted
+			}
+		}
+
+		if opts.OnResult != nil {
+			opts.OnResult(results[i])
+		}
+
+		return results[i].Err == nil
+	}, func(i int) {
+		results[i] = PruneResult{
+			Package: plan.Delete[i],
+			Status:  PruneStatusSkipped,
+		}
+	})
+
+	var failures []PruneResult
+	for _, result := range results {
+		if result.Status == PruneStatusFailed {
+			failures = append(failures, result)
+		}
+	}
+	if len(failures) > 0 {
+		return results, &PruneFailedError{
+			Failures: failures,
+			Total:    len(results),
+		}
+	}
+
+	return results, nil
+}
+
+// DestroyPackage deletes a package from its repository using its destroy
+// url.
+func (c *Client) DestroyPackage(pkg types.PackageFragment) error {
+	if isEmptyString(pkg.DestroyURL) {
+		return fmt.Errorf("package %s has no destroy url", pkg.Filename)
+	}
+
+	destroyURL, err := url.Parse(pkg.DestroyURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse destroy url: %s", err)
+	}
+
+	endpoint := c.getURL(destroyURL)
+
+	if _, err := c.apiRequest("DELETE", endpoint.String(), nil, "application/json"); err != nil {
+		return err
+	}
+	return nil
+}
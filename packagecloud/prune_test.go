@@ -0,0 +1,233 @@
+package packagecloud
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+func prunePackage(name, version, distro, arch, created string) types.PackageFragment {
+	pkg := fakePackage("user/repo", distro, arch, name, version)
+	pkg.CreatedAt = created
+	return pkg
+}
+
+func filenames(packages types.PackageFragments) []string {
+	names := []string{}
+	for _, pkg := range packages {
+		names = append(names, pkg.DistroVersion+"/"+pkg.Filename)
+	}
+	return names
+}
+
+func TestPlanPrune(t *testing.T) {
+	packages := types.PackageFragments{
+		prunePackage("agent", "1.9.0", "ubuntu/jammy", "amd64", "2024-01-01T00:00:00.000Z"),
+		prunePackage("agent", "1.10.0", "ubuntu/jammy", "amd64", "2024-02-01T00:00:00.000Z"),
+		prunePackage("agent", "1.8.0", "ubuntu/jammy", "amd64", "2023-12-01T00:00:00.000Z"),
+		prunePackage("agent", "1.8.0", "ubuntu/jammy", "arm64", "2023-12-01T00:00:00.000Z"),
+		prunePackage("agent", "1.7.0", "ubuntu/jammy", "amd64", "2023-11-01T00:00:00.000Z"),
+		prunePackage("agent", "1.9.0", "ubuntu/noble", "amd64", "2024-01-01T00:00:00.000Z"),
+		prunePackage("agent", "nightly", "ubuntu/jammy", "amd64", "2023-01-01T00:00:00.000Z"),
+	}
+
+	tests := []struct {
+		name     string
+		options  PruneOptions
+		expected []string
+	}{
+		{
+			name:    "per distro and arch",
+			options: PruneOptions{Keep: 2, PerDistro: true, PerArch: true},
+			expected: []string{
+				"ubuntu/jammy/agent_1.8.0-1_amd64.deb",
+				"ubuntu/jammy/agent_1.7.0-1_amd64.deb",
+			},
+		},
+		{
+			name:    "per distro",
+			options: PruneOptions{Keep: 3, PerDistro: true},
+			expected: []string{
+				"ubuntu/jammy/agent_1.7.0-1_amd64.deb",
+			},
+		},
+		{
+			name:    "across distros",
+			options: PruneOptions{Keep: 1},
+			expected: []string{
+				"ubuntu/jammy/agent_1.9.0-1_amd64.deb",
+				"ubuntu/noble/agent_1.9.0-1_amd64.deb",
+				"ubuntu/jammy/agent_1.8.0-1_amd64.deb",
+				"ubuntu/jammy/agent_1.8.0-1_arm64.deb",
+				"ubuntu/jammy/agent_1.7.0-1_amd64.deb",
+			},
+		},
+		{
+			name:    "protected",
+			options: PruneOptions{Keep: 1, PerDistro: true, PerArch: true, Protected: []string{"1.8.*"}},
+			expected: []string{
+				"ubuntu/jammy/agent_1.9.0-1_amd64.deb",
+				"ubuntu/jammy/agent_1.7.0-1_amd64.deb",
+			},
+		},
+		{
+			name:    "older than",
+			options: PruneOptions{Keep: 1, PerDistro: true, PerArch: true, OlderThan: time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)},
+			expected: []string{
+				"ubuntu/jammy/agent_1.8.0-1_amd64.deb",
+				"ubuntu/jammy/agent_1.7.0-1_amd64.deb",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append(types.PackageFragments{}, packages...)
+			plan := planPrune(tt.options, input)
+			if got := filenames(plan.Delete); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected to delete %v, got %v", tt.expected, got)
+			}
+			if plan.Kept[PruneKeepInvalidVersion] != 1 {
+				t.Errorf("expected the package with an invalid version to be kept, got %v", plan.Kept)
+			}
+		})
+	}
+}
+
+func TestPruneOptionsValidate(t *testing.T) {
+	repo := NewRepo("user", "repo")
+	if err := (PruneOptions{Repo: repo}).Validate(); err == nil {
+		t.Error("expected an error when keeping no versions")
+	}
+	if err := (PruneOptions{Repo: repo, Keep: 1, Protected: []string{"["}}).Validate(); err == nil {
+		t.Error("expected an error for an invalid protected version")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	packages := types.PackageFragments{
+		prunePackage("agent", "1.0.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("agent", "1.1.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("agent", "1.2.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("other", "1.0.0", "ubuntu/jammy", "amd64", ""),
+	}
+	server := newFakeServer(t, map[string]types.PackageFragments{"user/repo": packages})
+	client := server.client()
+
+	plan, err := client.PlanPrune(PruneOptions{
+		Repo:        NewRepo("user", "repo"),
+		Keep:        1,
+		PackageName: "agent",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.ExecutePrune(plan, PruneExecuteOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Status != PruneStatusDeleted {
+			t.Errorf("expected %s to be deleted, got %s", result.Package.Filename, result.Status)
+		}
+	}
+
+	expected := []string{
+		"DELETE /api/v1/repos/user/repo/ubuntu/jammy/agent_1.0.0-1_amd64.deb",
+		"DELETE /api/v1/repos/user/repo/ubuntu/jammy/agent_1.1.0-1_amd64.deb",
+	}
+	if got := server.changes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be deleted, got %v", expected, got)
+	}
+}
+
+func TestPruneQuery(t *testing.T) {
+	packages := types.PackageFragments{
+		prunePackage("agent", "1.0.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("agent", "1.1.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("agent", "1.2.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("other", "1.0.0", "ubuntu/jammy", "amd64", ""),
+	}
+	client := newFakeServer(t, map[string]types.PackageFragments{"user/repo": packages}).client()
+
+	query, err := ParseQuery("name:agent version:<1.2", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := client.PlanPrune(PruneOptions{
+		Repo:  NewRepo("user", "repo"),
+		Keep:  1,
+		Query: query,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"ubuntu/jammy/agent_1.0.0-1_amd64.deb"}
+	if got := filenames(plan.Delete); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected to delete %v, got %v", expected, got)
+	}
+}
+
+func TestPruneMaxDeletions(t *testing.T) {
+	server := newFakeServer(t, nil)
+	client := server.client()
+
+	plan := &PrunePlan{Delete: types.PackageFragments{
+		prunePackage("agent", "1.0.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("agent", "1.1.0", "ubuntu/jammy", "amd64", ""),
+	}}
+
+	_, err := client.ExecutePrune(plan, PruneExecuteOptions{MaxDeletions: 1})
+	var limitErr *PruneLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected a *PruneLimitError, got %v", err)
+	}
+	if changes := server.changes(); len(changes) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", changes)
+	}
+}
+
+func TestPruneStopsOnError(t *testing.T) {
+	plan := &PrunePlan{Delete: types.PackageFragments{
+		prunePackage("agent", "1.1.0", "ubuntu/jammy", "amd64", ""),
+		prunePackage("agent", "1.0.0", "ubuntu/jammy", "amd64", ""),
+	}}
+	server := newFakeServer(t, nil)
+	server.fail(plan.Delete[0].DestroyURL)
+	client := server.client()
+
+	results, err := client.ExecutePrune(plan, PruneExecuteOptions{})
+	var failedErr *PruneFailedError
+	if !errors.As(err, &failedErr) {
+		t.Fatalf("expected a *PruneFailedError, got %v", err)
+	}
+	if results[0].Status != PruneStatusFailed || results[1].Status != PruneStatusSkipped {
+		t.Errorf("expected failed and skipped results, got %s and %s", results[0].Status, results[1].Status)
+	}
+	if changes := server.changes(); len(changes) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", changes)
+	}
+}
+
+func TestPruneDryRun(t *testing.T) {
+	server := newFakeServer(t, nil)
+	client := NewClient(Config{ServiceURL: server.URL, Token: "token", DryRun: true})
+
+	plan := &PrunePlan{Delete: types.PackageFragments{prunePackage("agent", "1.0.0", "ubuntu/jammy", "amd64", "")}}
+	results, err := client.ExecutePrune(plan, PruneExecuteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != PruneStatusPlanned {
+		t.Errorf("expected planned, got %s", results[0].Status)
+	}
+	if changes := server.changes(); len(changes) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", changes)
+	}
+}
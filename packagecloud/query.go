@@ -0,0 +1,371 @@
+package packagecloud
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// Query is a parsed package query such as:
+//
+//	name:ecorp-agent version:>=1.4 arch:amd64 dist:ubuntu/* uploaded:<30d
+//
+// A query is a list of space separated terms, all of which must match. Each
+// term is either a field:value pair or a bare word that must appear in the
+// package filename. Values containing spaces can be quoted (i.e.
+// version:">=1.4, <2") and a term can be negated with a leading - (i.e.
+// -arch:i386). The supported fields are:
+//
+//	name       exact name, glob (ecorp-*) or regular expression (/^ecorp-/)
+//	filename   text the filename must contain
+//	version    version constraint (i.e. >=1.4, ~3.2, ">=1.4, <2")
+//	arch       architecture, glob allowed (i.e. amd64, arm*)
+//	dist       distro or distro version, glob allowed (i.e. el, ubuntu/*)
+//	type       package type (i.e. deb, rpm, gem)
+//	uploaded   upload time with < or > and a date, timestamp or age (i.e.
+//	           uploaded:>2024-01-02, uploaded:<30d for the last 30 days)
+//	uploader   name of the uploader
+//	indexed    true or false
+//	private    true or false
+type Query struct {
+	terms []queryTerm
+}
+
+type queryTerm struct {
+	negated bool
+
+	// filter is applied client-side to the search results.
+	filter FragmentFilter
+
+	// hint narrows the search server-side, if possible. Hints only ever
+	// return a superset of the packages matched by filter.
+	hint func(options *SearchOptions)
+}
+
+// queryFields are the fields that can be used in a query, with the function
+// that parses their values.
+var queryFields = map[string]func(value string, now time.Time) (queryTerm, error){
+	"name":     parseNameTerm,
+	"filename": parseFilenameTerm,
+	"version":  parseVersionTerm,
+	"arch":     parseArchTerm,
+	"dist":     parseDistTerm,
+	"type":     parseTypeTerm,
+	"uploaded": parseUploadedTerm,
+	"uploader": parseUploaderTerm,
+	"indexed":  parseIndexedTerm,
+	"private":  parsePrivateTerm,
+}
+
+// searchFilterTypes maps package types to the values of the search API's
+// filter parameter.
+var searchFilterTypes = map[string]string{
+	"deb":    "Debs",
+	"rpm":    "RPMs",
+	"dsc":    "DSCs",
+	"gem":    "Gem",
+	"python": "Python",
+	"node":   "Node",
+}
+
+// ParseQuery parses a package query. Relative times (i.e. uploaded:<30d) are
+// relative to now. Syntax errors are returned as a *QuerySyntaxError.
+func ParseQuery(query string, now time.Time) (*Query, error) {
+	q := &Query{}
+
+	syntaxError := func(pos int, format string, args ...interface{}) error {
+		return &QuerySyntaxError{
+			Query: query,
+			Pos:   pos,
+			Msg:   fmt.Sprintf(format, args...),
+		}
+	}
+
+	i := 0
+	for {
+		for i < len(query) && query[i] == ' ' {
+			i++
+		}
+		if i == len(query) {
+			break
+		}
+
+		negated := false
+		if query[i] == '-' && i+1 < len(query) && query[i+1] != ' ' {
+			negated = true
+			i++
+		}
+
+		// Find the end of the field name, if there is one.
+		field := ""
+		fieldPos := i
+		j := i
+		for j < len(query) && query[j] != ' ' && query[j] != ':' && query[j] != '"' {
+			j++
+		}
+		if j < len(query) && query[j] == ':' {
+			field = strings.ToLower(query[i:j])
+			if field == "" {
+				return nil, syntaxError(i, "missing field name before :")
+			}
+			if _, ok := queryFields[field]; !ok {
+				return nil, syntaxError(fieldPos, "unknown field %q (must be one of: %s)", field, strings.Join(queryFieldNames(), ", "))
+			}
+			i = j + 1
+		}
+
+		valuePos := i
+		value, end, err := readQueryValue(query, i)
+		if err != nil {
+			return nil, err
+		}
+		i = end
+
+		if value == "" {
+			if field != "" {
+				return nil, syntaxError(valuePos, "missing value for %s", field)
+			}
+			return nil, syntaxError(valuePos, "empty search text")
+		}
+
+		var term queryTerm
+		if field == "" {
+			term, err = parseFilenameTerm(value, now)
+		} else {
+			term, err = queryFields[field](value, now)
+		}
+		if err != nil {
+			return nil, syntaxError(valuePos, "%s", err)
+		}
+
+		term.negated = negated
+		q.terms = append(q.terms, term)
+	}
+
+	return q, nil
+}
+
+// readQueryValue reads the (optionally quoted) value starting at query[i],
+// returning the unquoted value and the offset just after it.
+func readQueryValue(query string, i int) (string, int, error) {
+	if i < len(query) && query[i] == '"' {
+		start := i
+		var value strings.Builder
+		i++
+		for ; i < len(query); i++ {
+			switch query[i] {
+			case '\\':
+				if i+1 < len(query) {
+					i++
+				}
+				value.WriteByte(query[i])
+				continue
+			case '"':
+				i++
+				if i < len(query) && query[i] != ' ' {
+					return "", 0, &QuerySyntaxError{Query: query, Pos: i, Msg: "expected a space after closing quote"}
+				}
+				return value.String(), i, nil
+			}
+			value.WriteByte(query[i])
+		}
+		return "", 0, &QuerySyntaxError{Query: query, Pos: start, Msg: "unterminated quote"}
+	}
+
+	start := i
+	for ; i < len(query) && query[i] != ' '; i++ {
+		if query[i] == '"' {
+			return "", 0, &QuerySyntaxError{Query: query, Pos: i, Msg: "unexpected quote, quote the whole value instead"}
+		}
+	}
+	return query[start:i], i, nil
+}
+
+func queryFieldNames() []string {
+	names := make([]string, 0, len(queryFields))
+	for name := range queryFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply adds the query to options. Terms are always applied as client-side
+// filters, and also narrow the server-side search where the corresponding
+// option is not already set.
+func (q *Query) Apply(options *SearchOptions) {
+	for _, term := range q.terms {
+		if term.negated {
+			options.Filters = append(options.Filters, NotFilter(term.filter))
+			continue
+		}
+
+		if term.hint != nil {
+			term.hint(options)
+		}
+		options.Filters = append(options.Filters, term.filter)
+	}
+}
+
+func hasGlob(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+func parseNameTerm(value string, _ time.Time) (queryTerm, error) {
+	if len(value) >= 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+		re, err := regexp.Compile(value[1 : len(value)-1])
+		if err != nil {
+			return queryTerm{}, fmt.Errorf("invalid name regular expression: %w", err)
+		}
+		return queryTerm{filter: NameRegexpFilter(re)}, nil
+	}
+
+	if hasGlob(value) {
+		if _, err := path.Match(value, ""); err != nil {
+			return queryTerm{}, fmt.Errorf("invalid name pattern %q: %w", value, err)
+		}
+		return queryTerm{
+			filter: func(pkg types.PackageFragment) bool {
+				ok, _ := path.Match(value, pkg.Name)
+				return ok
+			},
+		}, nil
+	}
+
+	// The name is not used to narrow the search by filename, as filenames
+	// don't always contain the name as is (i.e. python normalizes - to _).
+	return queryTerm{filter: NameFilter(value)}, nil
+}
+
+func parseFilenameTerm(value string, _ time.Time) (queryTerm, error) {
+	return queryTerm{
+		filter: FilenameFilter(value),
+		hint: func(options *SearchOptions) {
+			if options.Query == "" {
+				options.Query = value
+			}
+		},
+	}, nil
+}
+
+func parseVersionTerm(value string, _ time.Time) (queryTerm, error) {
+	constraint, err := types.ParseVersionConstraint(value)
+	if err != nil {
+		return queryTerm{}, err
+	}
+	return queryTerm{filter: VersionFilter(constraint, nil)}, nil
+}
+
+func parseArchTerm(value string, _ time.Time) (queryTerm, error) {
+	if _, err := path.Match(value, ""); err != nil {
+		return queryTerm{}, fmt.Errorf("invalid arch pattern %q: %w", value, err)
+	}
+
+	term := queryTerm{filter: ArchFilter(value)}
+	if !hasGlob(value) {
+		term.hint = func(options *SearchOptions) {
+			if options.Arch == "" {
+				options.Arch = value
+			}
+		}
+	}
+	return term, nil
+}
+
+func parseDistTerm(value string, _ time.Time) (queryTerm, error) {
+	if _, err := path.Match(value, ""); err != nil {
+		return queryTerm{}, fmt.Errorf("invalid dist pattern %q: %w", value, err)
+	}
+
+	// The search API accepts a distro (i.e. ubuntu) or a distro version (i.e.
+	// ubuntu/jammy), so search for the distro when only the version is a
+	// pattern.
+	dist := value
+	if hasGlob(value) {
+		distro, version, ok := strings.Cut(value, "/")
+		if !ok || hasGlob(distro) || strings.Contains(version, "/") {
+			dist = ""
+		} else {
+			dist = distro
+		}
+	}
+
+	term := queryTerm{filter: DistroFilter(value)}
+	if dist != "" {
+		term.hint = func(options *SearchOptions) {
+			if options.Dist == "" {
+				options.Dist = dist
+			}
+		}
+	}
+	return term, nil
+}
+
+func parseTypeTerm(value string, _ time.Time) (queryTerm, error) {
+	packageType := strings.ToLower(value)
+
+	term := queryTerm{filter: TypeFilter(packageType)}
+	if filter, ok := searchFilterTypes[packageType]; ok {
+		term.hint = func(options *SearchOptions) {
+			if options.Filter == "" && options.Dist == "" {
+				options.Filter = filter
+			}
+		}
+	}
+	return term, nil
+}
+
+func parseUploadedTerm(value string, now time.Time) (queryTerm, error) {
+	var before bool
+	switch {
+	case strings.HasPrefix(value, "<"):
+		before = true
+	case strings.HasPrefix(value, ">"):
+		before = false
+	default:
+		return queryTerm{}, fmt.Errorf("expected < or > before upload time (i.e. uploaded:<30d, uploaded:>2024-01-02)")
+	}
+	value = strings.TrimPrefix(value[1:], "=")
+
+	t, err := ParseUploadTime(value, now)
+	if err != nil {
+		return queryTerm{}, err
+	}
+
+	// Ages are compared by age, so uploaded:<30d is uploaded less than 30
+	// days ago, which is after the point in time 30 days ago.
+	if relativeTimePattern.MatchString(value) {
+		before = !before
+	}
+
+	if before {
+		return queryTerm{filter: UploadedBeforeFilter(t)}, nil
+	}
+	return queryTerm{filter: UploadedAfterFilter(t)}, nil
+}
+
+func parseUploaderTerm(value string, _ time.Time) (queryTerm, error) {
+	return queryTerm{filter: UploaderFilter(value)}, nil
+}
+
+func parseIndexedTerm(value string, _ time.Time) (queryTerm, error) {
+	indexed, err := strconv.ParseBool(value)
+	if err != nil {
+		return queryTerm{}, fmt.Errorf("invalid value %q for indexed (must be true or false)", value)
+	}
+	return queryTerm{filter: IndexedFilter(indexed)}, nil
+}
+
+func parsePrivateTerm(value string, _ time.Time) (queryTerm, error) {
+	private, err := strconv.ParseBool(value)
+	if err != nil {
+		return queryTerm{}, fmt.Errorf("invalid value %q for private (must be true or false)", value)
+	}
+	return queryTerm{filter: PrivateFilter(private)}, nil
+}
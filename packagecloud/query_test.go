@@ -0,0 +1,196 @@
+package packagecloud
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	query, err := ParseQuery(`name:ecorp-agent version:">=1.4, <2" arch:amd64 dist:ubuntu/* uploaded:<30d -indexed:false`, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := SearchOptions{RepoUser: "ecorp", RepoName: "production"}
+	query.Apply(&options)
+
+	if options.Query != "" {
+		t.Errorf("expected no server-side query, got %q", options.Query)
+	}
+	if options.Arch != "amd64" {
+		t.Errorf("expected server-side arch amd64, got %q", options.Arch)
+	}
+	if options.Dist != "ubuntu" {
+		t.Errorf("expected server-side dist ubuntu, got %q", options.Dist)
+	}
+	if err := options.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	match := AllFilters(options.Filters...)
+	pkg := types.PackageFragment{
+		Name:          "ecorp-agent",
+		Version:       "1.4.3",
+		Release:       "1",
+		Type:          "deb",
+		Architecture:  "amd64",
+		DistroVersion: "ubuntu/jammy",
+		CreatedAt:     "2024-03-20T00:00:00.000Z",
+		Indexed:       true,
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(pkg *types.PackageFragment)
+		expected bool
+	}{
+		{"match", func(pkg *types.PackageFragment) {}, true},
+		{"name", func(pkg *types.PackageFragment) { pkg.Name = "ecorp-agent-dbg" }, false},
+		{"version", func(pkg *types.PackageFragment) { pkg.Version = "2.0.0" }, false},
+		{"arch", func(pkg *types.PackageFragment) { pkg.Architecture = "arm64" }, false},
+		{"dist", func(pkg *types.PackageFragment) { pkg.DistroVersion = "debian/bookworm" }, false},
+		{"uploaded", func(pkg *types.PackageFragment) { pkg.CreatedAt = "2024-02-01T00:00:00.000Z" }, false},
+		{"indexed", func(pkg *types.PackageFragment) { pkg.Indexed = false }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := pkg
+			tt.modify(&pkg)
+			if got := match(pkg); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseQueryTerms(t *testing.T) {
+	now := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	pkg := types.PackageFragment{
+		Name:          "ecorp-agent",
+		Filename:      "ecorp-agent_1.4.3-1_amd64.deb",
+		Type:          "deb",
+		Architecture:  "amd64",
+		DistroVersion: "el/9",
+		CreatedAt:     "2024-01-15T00:00:00.000Z",
+		UploaderName:  "alice",
+	}
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"ecorp-agent_1.4", true},
+		{`"1.4.3-1 amd64"`, false},
+		{"filename:1.4.3", true},
+		{"name:ecorp-*", true},
+		{"name:/^ecorp-(agent|cli)$/", true},
+		{"name:ecorp", false},
+		{"arch:arm*", false},
+		{"-arch:arm*", true},
+		{"dist:el", true},
+		{"dist:el/[89]", true},
+		{"dist:ubuntu/*", false},
+		{"type:DEB", true},
+		{"type:rpm", false},
+		{"uploaded:>2024-01-01", true},
+		{"uploaded:<2024-01-01", false},
+		{"uploaded:>30d", true},
+		{"uploader:alice", true},
+		{"UPLOADER:bob", false},
+		{"private:false", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := ParseQuery(tt.query, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var options SearchOptions
+			query.Apply(&options)
+			if got := AllFilters(options.Filters...)(pkg); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseQueryServerSideOptions(t *testing.T) {
+	query, err := ParseQuery("1.4.3 type:rpm arch:x86_64 dist:el/9", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := SearchOptions{Arch: "aarch64"}
+	query.Apply(&options)
+
+	expected := SearchOptions{
+		Query:  "1.4.3",
+		Filter: "RPMs",
+		Dist:   "el/9",
+		// Options that are already set are left alone.
+		Arch: "aarch64",
+	}
+	if options.Query != expected.Query || options.Filter != expected.Filter || options.Dist != expected.Dist || options.Arch != expected.Arch {
+		t.Errorf("expected %+v, got %+v", expected, options)
+	}
+	if len(options.Filters) != 4 {
+		t.Errorf("expected 4 client-side filters, got %d", len(options.Filters))
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"name:agent color:blue", 11, "unknown field"},
+		{"name:agent version:", 19, "missing value for version"},
+		{"name:agent version:>=1.x.y", 19, "version"},
+		{`name:agent version:">=1.4`, 19, "unterminated quote"},
+		{`name:"agent"x`, 12, "expected a space after closing quote"},
+		{`name:age"nt`, 8, "unexpected quote"},
+		{"name:/[/", 5, "invalid name regular expression"},
+		{"arch:amd64 uploaded:30d", 20, "expected < or >"},
+		{"uploaded:<yesterday", 9, "invalid time"},
+		{"indexed:maybe", 8, "must be true or false"},
+		{":agent", 0, "missing field name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query, time.Now())
+
+			var syntaxErr *QuerySyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected a *QuerySyntaxError, got %v", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("expected position %d, got %d", tt.pos, syntaxErr.Pos)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("expected message containing %q, got %q", tt.msg, syntaxErr.Msg)
+			}
+		})
+	}
+}
+
+func TestQuerySyntaxErrorPointsAtPosition(t *testing.T) {
+	err := &QuerySyntaxError{Query: "name:agent color:blue", Pos: 11, Msg: `unknown field "color"`}
+
+	expected := "invalid query at position 12: unknown field \"color\"\n" +
+		"  name:agent color:blue\n" +
+		"             ^"
+	if err.Error() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package packagecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+const (
+	reposPath = "/api/v1/repos.json"
+)
+
+// ListRepos returns every repository the token has access to.
+func (c *Client) ListRepos() ([]types.Repository, error) {
+	reposURL, err := url.Parse(reposPath)
+	if err != nil {
+		return nil, fmt.Errorf("this is a bug, failed to parse relative url: %s", err)
+	}
+
+	endpoint := c.getURL(reposURL)
+
+	var repos []types.Repository
+	mu := &sync.Mutex{}
+
+	if err := c.paginatedRequest("GET", endpoint.String(), nil, "application/json", func(bytes []byte) error {
+		var page []types.Repository
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return &UnmarshalError{
+				Data: bytes,
+				Err:  err,
+			}
+		}
+
+		mu.Lock()
+		repos = append(repos, page...)
+		mu.Unlock()
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].FQName < repos[j].FQName
+	})
+
+	return repos, nil
+}
+
+// IsRepoPattern returns whether or not s is a repository or a pattern of
+// repositories (i.e. ecorp/production, ecorp/*).
+func IsRepoPattern(s string) bool {
+	user, name, ok := strings.Cut(s, "/")
+	return ok && user != "" && name != "" &&
+		!strings.ContainsAny(s, " \t:\"") && !strings.Contains(name, "/")
+}
+
+// ExpandRepos returns the repositories matching patterns, in the order they
+// are given and without duplicates. Patterns are either a repository
+// (user/repo) or a pattern in the syntax of path.Match (i.e. ecorp/*,
+// ecorp/prod-*), which is expanded through the list of repositories the
+// token has access to.
+func (c *Client) ExpandRepos(patterns []string) ([]Repo, error) {
+	var repos []Repo
+	seen := map[Repo]bool{}
+	add := func(repo Repo) {
+		if !seen[repo] {
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+
+	var available []types.Repository
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, `*?[\`) {
+			repo, err := NewRepoFromString(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid repo %q: %w", pattern, err)
+			}
+			add(repo)
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid repo pattern %q: %w", pattern, err)
+		}
+
+		if available == nil {
+			var err error
+			available, err = c.ListRepos()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list repositories: %w", err)
+			}
+		}
+
+		matched := false
+		for _, r := range available {
+			if ok, _ := path.Match(pattern, r.FQName); !ok {
+				continue
+			}
+			repo, err := NewRepoFromString(r.FQName)
+			if err != nil {
+				continue
+			}
+			add(repo)
+			matched = true
+		}
+		if !matched {
+			return nil, fmt.Errorf("no repositories match %s", pattern)
+		}
+	}
+
+	return repos, nil
+}
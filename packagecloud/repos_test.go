@@ -0,0 +1,104 @@
+package packagecloud
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// newReposServer returns a fake server with the repositories of ecorp and
+// other, where searching ecorp/prod-legacy fails.
+func newReposServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	server := newFakeServer(t, map[string]types.PackageFragments{
+		"ecorp/production": {fakePackage("ecorp/production", "ubuntu/jammy", "amd64", "agent", "1.0")},
+		"ecorp/staging":    {fakePackage("ecorp/staging", "ubuntu/jammy", "amd64", "agent", "1.1")},
+	})
+	server.repositories = []types.Repository{
+		{Name: "staging", FQName: "ecorp/staging"},
+		{Name: "production", FQName: "ecorp/production"},
+		{Name: "prod-legacy", FQName: "ecorp/prod-legacy"},
+		{Name: "tools", FQName: "other/tools"},
+	}
+	server.fail("/api/v1/repos/ecorp/prod-legacy/search.json")
+	return server
+}
+
+func TestExpandRepos(t *testing.T) {
+	server := newReposServer(t)
+	client := server.client()
+
+	repos, err := client.ExpandRepos([]string{"ecorp/staging", "ecorp/prod*", "ecorp/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Repo{
+		NewRepo("ecorp", "staging"),
+		NewRepo("ecorp", "prod-legacy"),
+		NewRepo("ecorp", "production"),
+	}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Errorf("expected %v, got %v", expected, repos)
+	}
+	if n := server.count(http.MethodGet, reposPath); n != 1 {
+		t.Errorf("expected repositories to be listed once, got %d", n)
+	}
+}
+
+func TestExpandReposNoMatch(t *testing.T) {
+	client := newReposServer(t).client()
+
+	if _, err := client.ExpandRepos([]string{"nobody/*"}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestIsRepoPattern(t *testing.T) {
+	tests := map[string]bool{
+		"ecorp/production":  true,
+		"ecorp/*":           true,
+		"ecorp":             false,
+		"ecorp/":            false,
+		"dist:ubuntu/*":     false,
+		"a/b/c":             false,
+		"name:agent arch:x": false,
+	}
+	for s, expected := range tests {
+		if got := IsRepoPattern(s); got != expected {
+			t.Errorf("IsRepoPattern(%q) = %v, expected %v", s, got, expected)
+		}
+	}
+}
+
+func TestSearchRepos(t *testing.T) {
+	client := newReposServer(t).client()
+
+	repos := []Repo{
+		NewRepo("ecorp", "production"),
+		NewRepo("ecorp", "prod-legacy"),
+		NewRepo("ecorp", "staging"),
+	}
+	results := client.SearchRepos(repos, SearchOptions{Query: "agent"}, 2)
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.Repo != repos[i] {
+			t.Errorf("expected result %d to be for %s, got %s", i, repos[i], result.Repo)
+		}
+	}
+	if results[0].Err != nil || len(results[0].Packages) != 1 || results[0].Packages[0].Filename != "agent_1.0-1_amd64.deb" {
+		t.Errorf("unexpected result for %s: %+v", repos[0], results[0])
+	}
+	if results[1].Err == nil {
+		t.Errorf("expected an error for %s", repos[1])
+	}
+	if results[2].Err != nil || len(results[2].Packages) != 1 {
+		t.Errorf("expected the search of %s to continue after a failure, got %+v", repos[2], results[2])
+	}
+}
@@ -42,17 +42,21 @@ type SearchOptions struct {
 	// PerPage is the number of packages to return from the results set. If
 	// nothing passed the default is 30.
 	PerPage string
+
+	// Filters are applied client-side to the packages returned by the
+	// server. Only packages matching every filter are returned.
+	Filters []FragmentFilter
 }
 
 func (o SearchOptions) Validate() error {
-	if o.Query == "" && o.Filter == "" && o.Dist == "" && o.Arch == "" {
+	if o.Query == "" && o.Filter == "" && o.Dist == "" && o.Arch == "" && len(o.Filters) == 0 {
 		return &MissingSearchOptionsError{}
 	}
 	return nil
 }
 
 func (c *Client) Search(options SearchOptions) (types.PackageFragments, error) {
-	var packages types.PackageFragments
+	packages := types.PackageFragments{}
 	var mu = &sync.RWMutex{}
 
 	if err := c.SearchStream(options, func(streamPackages types.PackageFragments) {
@@ -110,8 +114,84 @@ func (c *Client) SearchStream(options SearchOptions, fn func(types.PackageFragme
 				Err:  err,
 			}
 		}
-		fn(packages)
+		fn(options.filter(packages))
 
 		return nil
 	})
 }
+
+// findPackages returns the packages matching options. When options has no
+// server-side search parameters, a search would match every package of the
+// repository anyway, so they are listed with ListPackagesStream instead and
+// only filtered client-side.
+func (c *Client) findPackages(options SearchOptions) (types.PackageFragments, error) {
+	var packages types.PackageFragments
+	mu := &sync.Mutex{}
+	collect := func(page types.PackageFragments) {
+		mu.Lock()
+		packages = append(packages, page...)
+		mu.Unlock()
+	}
+
+	var err error
+	if options.Query != "" || options.Filter != "" || options.Dist != "" || options.Arch != "" {
+		err = c.SearchStream(options, collect)
+	} else {
+		err = c.ListPackagesStream(NewRepo(options.RepoUser, options.RepoName), func(page types.PackageFragments) {
+			collect(options.filter(page))
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+// filter returns the packages matching every one of the options' filters.
+func (o SearchOptions) filter(packages types.PackageFragments) types.PackageFragments {
+	if len(o.Filters) == 0 {
+		return packages
+	}
+
+	match := AllFilters(o.Filters...)
+	filtered := types.PackageFragments{}
+	for _, pkg := range packages {
+		if match(pkg) {
+			filtered = append(filtered, pkg)
+		}
+	}
+	return filtered
+}
+
+// RepoSearchResult is the result of searching one of several repositories.
+type RepoSearchResult struct {
+	Repo     Repo
+	Packages types.PackageFragments
+	Err      error
+}
+
+// SearchRepos searches each of repos with options, running up to
+// concurrency searches at the same time. The repository in options is
+// ignored. A result is returned for every repository, in the same order,
+// and a failed search does not stop the others.
+func (c *Client) SearchRepos(repos []Repo, options SearchOptions, concurrency int) []RepoSearchResult {
+	results := make([]RepoSearchResult, len(repos))
+
+	forEach(len(repos), concurrency, true, func(i int) bool {
+		repoOptions := options
+		repoOptions.RepoUser = repos[i].User
+		repoOptions.RepoName = repos[i].Name
+
+		packages, err := c.Search(repoOptions)
+		results[i] = RepoSearchResult{
+			Repo:     repos[i],
+			Packages: packages,
+			Err:      err,
+		}
+
+		return err == nil
+	}, func(int) {})
+
+	return results
+}
@@ -0,0 +1,287 @@
+package packagecloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// fakeServer is a fake packagecloud API. It serves the packages of each
+// repository from packages.json and search.json, the details of packages
+// from their package URLs and the contents of files, and records every
+// request that succeeds. Requests that don't read anything succeed with an
+// empty JSON object unless their path has been made to fail.
+type fakeServer struct {
+	*httptest.Server
+
+	t  *testing.T
+	mu sync.Mutex
+
+	repos         map[string]types.PackageFragments
+	next          map[string][]types.PackageFragments
+	details       map[string]types.PackageDetails
+	files         map[string]string
+	failures      map[string]bool
+	repositories  []types.Repository
+	distributions types.PackageTypes
+
+	requests []string
+	pushed   map[string]string
+}
+
+// newFakeServer returns a fake packagecloud API serving the given packages
+// of each repository (i.e. "ecorp/staging").
+func newFakeServer(t *testing.T, repos map[string]types.PackageFragments) *fakeServer {
+	t.Helper()
+
+	s := &fakeServer{
+		t:        t,
+		repos:    map[string]types.PackageFragments{},
+		next:     map[string][]types.PackageFragments{},
+		details:  map[string]types.PackageDetails{},
+		files:    map[string]string{},
+		failures: map[string]bool{},
+		pushed:   map[string]string{},
+	}
+	for repo, packages := range repos {
+		s.repos[repo] = packages
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// client returns a client of the server.
+func (s *fakeServer) client() *Client {
+	return NewClient(Config{ServiceURL: s.URL, Token: "token"})
+}
+
+// addDetails serves the details of pkg from its package URL.
+func (s *fakeServer) addDetails(pkg types.PackageFragment, details types.PackageDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.details[pkg.PackageURL] = details
+}
+
+// addFile serves body from path.
+func (s *fakeServer) addFile(path string, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path] = body
+}
+
+// fail makes every request to path fail.
+func (s *fakeServer) fail(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[path] = true
+}
+
+// setPackages replaces the packages of repo.
+func (s *fakeServer) setPackages(repo string, packages types.PackageFragments) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos[repo] = packages
+}
+
+// sequence changes the packages of repo to the next set of packages in
+// responses after each search of repo, stopping at the last one.
+func (s *fakeServer) sequence(repo string, responses ...types.PackageFragments) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos[repo] = responses[0]
+	s.next[repo] = responses[1:]
+}
+
+// count returns the number of requests received with the given method and
+// path.
+func (s *fakeServer) count(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, request := range s.requests {
+		if request == method+" "+path || strings.HasPrefix(request, method+" "+path+"?") {
+			n++
+		}
+	}
+	return n
+}
+
+// searches returns the number of searches of repo.
+func (s *fakeServer) searches(repo string) int {
+	return s.count(http.MethodGet, "/api/v1/repos/"+repo+"/search.json")
+}
+
+// changes returns the sorted requests that were not reads, as "METHOD
+// path[?query]".
+func (s *fakeServer) changes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes := []string{}
+	for _, request := range s.requests {
+		if !strings.HasPrefix(request, http.MethodGet+" ") {
+			changes = append(changes, request)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	request := r.Method + " " + r.URL.Path
+	if r.URL.RawQuery != "" {
+		request += "?" + r.URL.RawQuery
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures[r.URL.Path] {
+		http.Error(w, `{"error":"boom"}`, http.StatusInternalServerError)
+		return
+	}
+	s.requests = append(s.requests, request)
+
+	if r.Method != http.MethodGet {
+		if repo, ok := repoPath(r.URL.Path, "packages.json"); ok && r.Method == http.MethodPost {
+			s.push(w, r, repo)
+			return
+		}
+		w.Write([]byte("{}"))
+		return
+	}
+
+	if repo, ok := repoPath(r.URL.Path, "packages.json"); ok {
+		s.encode(w, s.repos[repo])
+		return
+	}
+
+	if repo, ok := repoPath(r.URL.Path, "search.json"); ok {
+		s.encode(w, searchPackages(s.repos[repo], r))
+		if next := s.next[repo]; len(next) > 0 {
+			s.repos[repo] = next[0]
+			s.next[repo] = next[1:]
+		}
+		return
+	}
+
+	switch r.URL.Path {
+	case reposPath:
+		s.encode(w, s.repositories)
+		return
+	case distributionsPath:
+		s.encode(w, s.distributions)
+		return
+	}
+
+	if details, ok := s.details[r.URL.Path]; ok {
+		s.encode(w, details)
+		return
+	}
+	if body, ok := s.files[r.URL.Path]; ok {
+		w.Write([]byte(body))
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// push records the filename and distro id of a package pushed to repo.
+func (s *fakeServer) push(w http.ResponseWriter, r *http.Request, repo string) {
+	file, header, err := r.FormFile("package[package_file]")
+	if err != nil {
+		s.t.Errorf("failed to read package pushed to %s: %s", repo, err)
+		http.Error(w, `{"error":"bad request"}`, http.StatusBadRequest)
+		return
+	}
+	file.Close()
+
+	s.pushed[header.Filename] = r.FormValue("package[distro_version_id]")
+	w.Write([]byte("{}"))
+}
+
+func (s *fakeServer) encode(w http.ResponseWriter, v interface{}) {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.t.Errorf("failed to encode response: %s", err)
+	}
+}
+
+// repoPath returns the repository of an API path of the form
+// /api/v1/repos/user/repo/<name>.
+func repoPath(path string, name string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/v1/repos/")
+	if !ok {
+		return "", false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[2] != name {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
+
+// searchPackages returns the packages matching the query, distribution and
+// architecture of a search request.
+func searchPackages(packages types.PackageFragments, r *http.Request) types.PackageFragments {
+	query := r.URL.Query()
+	matches := types.PackageFragments{}
+	for _, pkg := range packages {
+		if q := query.Get("q"); q != "" && !strings.Contains(pkg.Filename, q) {
+			continue
+		}
+		if dist := query.Get("dist"); dist != "" && pkg.DistroVersion != dist && !strings.HasPrefix(pkg.DistroVersion, dist+"/") {
+			continue
+		}
+		if arch := query.Get("arch"); arch != "" && pkg.Architecture != arch {
+			continue
+		}
+		matches = append(matches, pkg)
+	}
+	return matches
+}
+
+// fakePackage returns a deb package of repo for the given distro version
+// (i.e. ubuntu/jammy) and architecture, with the URLs the packagecloud API
+// gives packages.
+func fakePackage(repo, distro, arch, name, version string) types.PackageFragment {
+	filename := name + "_" + version + "-1_" + arch + ".deb"
+	return types.PackageFragment{
+		Name:          name,
+		Type:          "deb",
+		Version:       version,
+		Release:       "1",
+		DistroVersion: distro,
+		Architecture:  arch,
+		Filename:      filename,
+		PackageURL:    "/api/v1/repos/" + repo + "/package/deb/" + distro + "/" + filename + ".json",
+		PromoteURL:    "/api/v1/repos/" + repo + "/" + distro + "/" + filename + "/promote.json",
+		DestroyURL:    "/api/v1/repos/" + repo + "/" + distro + "/" + filename,
+	}
+}
+
+// newChecksumServer returns a fake server for repositories holding ubuntu/jammy
+// amd64 packages with the given name, version and sha256 checksum.
+func newChecksumServer(t *testing.T, repos map[string][][3]string) *fakeServer {
+	t.Helper()
+
+	server := newFakeServer(t, nil)
+	for repo, packages := range repos {
+		var fragments types.PackageFragments
+		for _, p := range packages {
+			pkg := fakePackage(repo, "ubuntu/jammy", "amd64", p[0], p[1])
+			server.addDetails(pkg, types.PackageDetails{Filename: pkg.Filename, SHA256Sum: p[2]})
+			fragments = append(fragments, pkg)
+		}
+		server.setPackages(repo, fragments)
+	}
+	return server
+}
@@ -0,0 +1,568 @@
+package packagecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// SnapshotFormatVersion is the version of the snapshot file format.
+const SnapshotFormatVersion = 1
+
+// Snapshot is a record of every package in a repository at a point in time.
+type Snapshot struct {
+	// Version is the version of the snapshot file format.
+	Version int `json:"version"`
+
+	// Repository is the repository the snapshot was taken of.
+	Repository string `json:"repository"`
+
+	// CreatedAt is when the snapshot was taken.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Packages are the packages of the repository, ordered by name, distro
+	// version, architecture and version.
+	Packages []SnapshotPackage `json:"packages"`
+}
+
+// SnapshotPackage is a package recorded in a snapshot.
+type SnapshotPackage struct {
+	// Name is the name of the package.
+	Name string `json:"name"`
+
+	// Type is the type of the package.
+	Type string `json:"type"`
+
+	// Version is the version key of the package (see
+	// types.PackageVersionKey), i.e. [epoch:]version[-release].
+	Version string `json:"version"`
+
+	// DistroVersion is the distro version of the package.
+	DistroVersion string `json:"distro_version"`
+
+	// Architecture is the architecture of the package.
+	Architecture string `json:"architecture"`
+
+	// Filename is the filename of the package.
+	Filename string `json:"filename"`
+
+	// Checksum is the strongest checksum available for the package, prefixed
+	// with its algorithm (i.e. sha256:2c26b4...).
+	Checksum string `json:"checksum,omitempty"`
+}
+
+func newSnapshotPackage(pkg types.PackageFragment) SnapshotPackage {
+	return SnapshotPackage{
+		Name:          pkg.Name,
+		Type:          pkg.Type,
+		Version:       types.PackageVersionKey(types.VersionSchemeForPackageType(pkg.Type), pkg.Epoch, pkg.Version, pkg.Release),
+		DistroVersion: pkg.DistroVersion,
+		Architecture:  pkg.Architecture,
+		Filename:      pkg.Filename,
+	}
+}
+
+func (p SnapshotPackage) key() string {
+	return p.DistroVersion + "/" + p.Filename
+}
+
+// ReadSnapshot decodes a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snapshot.Version != SnapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot version: %d", snapshot.Version)
+	}
+	return &snapshot, nil
+}
+
+// Write encodes the snapshot as indented JSON, so that snapshots can be
+// compared with a text diff.
+func (s *Snapshot) Write(w io.Writer) error {
+	bytes, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	bytes = append(bytes, '\n')
+	_, err = w.Write(bytes)
+	return err
+}
+
+type SnapshotOptions struct {
+	// Concurrency is the maximum number of packages to retrieve the details
+	// of at the same time. Defaults to 1.
+	Concurrency int
+}
+
+// ExportSnapshot records every package in repo along with its checksum,
+// which requires retrieving the details of every package.
+func (c *Client) ExportSnapshot(repo Repo, opts SnapshotOptions) (*Snapshot, error) {
+	if err := repo.Validate(); err != nil {
+		return nil, fmt.Errorf("repository validation failed: %w", err)
+	}
+
+	packages, err := c.ListPackages(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages of %s: %w", repo, err)
+	}
+
+	snapshot := &Snapshot{
+		Version:    SnapshotFormatVersion,
+		Repository: repo.String(),
+		CreatedAt:  time.Now().UTC(),
+		Packages:   make([]SnapshotPackage, len(packages)),
+	}
+	errs := make([]error, len(packages))
+
+	forEach(len(packages), opts.Concurrency, false, func(i int) bool {
+		snapshot.Packages[i] = newSnapshotPackage(packages[i])
+
+		details, err := c.GetPackageDetails(packages[i])
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to get package details: %w", err)
+			return false
+		}
+		if algorithm, sum := details.Checksum(); algorithm != "" {
+			snapshot.Packages[i].Checksum = algorithm + ":" + sum
+		}
+		return true
+	}, func(int) {})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(snapshot.Packages, func(i, j int) bool {
+		return lessSnapshotPackage(snapshot.Packages[i], snapshot.Packages[j])
+	})
+
+	return snapshot, nil
+}
+
+type DriftStatus string
+
+const (
+	// DriftStatusMissing indicates that a package of the snapshot no longer
+	// exists in the repository.
+	DriftStatusMissing DriftStatus = "missing"
+
+	// DriftStatusExtra indicates that a package of the repository is not in
+	// the snapshot.
+	DriftStatusExtra DriftStatus = "extra"
+
+	// DriftStatusChanged indicates that a package of the snapshot exists in
+	// the repository with a different checksum.
+	DriftStatusChanged DriftStatus = "changed"
+)
+
+// DriftEntry is a difference between a repository and a snapshot.
+type DriftEntry struct {
+	// Status is the kind of difference.
+	Status DriftStatus `json:"status"`
+
+	// SnapshotPackage is the package in the snapshot or, for extra
+	// packages, in the repository without its checksum.
+	SnapshotPackage
+
+	// ActualChecksum is the checksum of a changed package in the
+	// repository.
+	ActualChecksum string `json:"actual_checksum,omitempty"`
+}
+
+// SnapshotDrift is the outcome of verifying a repository against a
+// snapshot.
+type SnapshotDrift struct {
+	Repo Repo
+
+	// Entries are the differences between the repository and the snapshot,
+	// ordered by name, distro version, architecture and version.
+	Entries []DriftEntry
+
+	// Same is the number of packages of the snapshot that exist in the
+	// repository and are not different.
+	Same int
+}
+
+type VerifySnapshotOptions struct {
+	// SkipChecksums only verifies which packages exist in the repository,
+	// without retrieving their details to compare their checksums.
+	SkipChecksums bool
+
+	// Concurrency is the maximum number of packages to compare the
+	// checksums of at the same time. Defaults to 1.
+	Concurrency int
+}
+
+// VerifySnapshot compares the packages of repo with the packages recorded in
+// snapshot, which does not have to be a snapshot of repo. Packages are
+// matched by distro version and filename.
+func (c *Client) VerifySnapshot(repo Repo, snapshot *Snapshot, opts VerifySnapshotOptions) (*SnapshotDrift, error) {
+	if err := repo.Validate(); err != nil {
+		return nil, fmt.Errorf("repository validation failed: %w", err)
+	}
+
+	packages, err := c.ListPackages(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages of %s: %w", repo, err)
+	}
+
+	byKey := make(map[string]types.PackageFragment, len(packages))
+	for _, pkg := range packages {
+		byKey[pkg.DistroVersion+"/"+pkg.Filename] = pkg
+	}
+
+	drift := &SnapshotDrift{
+		Repo:    repo,
+		Entries: []DriftEntry{},
+	}
+
+	var common []SnapshotPackage
+	recorded := make(map[string]bool, len(snapshot.Packages))
+	for _, expected := range snapshot.Packages {
+		recorded[expected.key()] = true
+		if _, ok := byKey[expected.key()]; ok {
+			common = append(common, expected)
+		} else {
+			drift.Entries = append(drift.Entries, DriftEntry{Status: DriftStatusMissing, SnapshotPackage: expected})
+		}
+	}
+	for key, pkg := range byKey {
+		if !recorded[key] {
+			drift.Entries = append(drift.Entries, DriftEntry{Status: DriftStatusExtra, SnapshotPackage: newSnapshotPackage(pkg)})
+		}
+	}
+
+	if opts.SkipChecksums {
+		drift.Same = len(common)
+	} else {
+		actual := make([]string, len(common))
+		errs := make([]error, len(common))
+
+		forEach(len(common), opts.Concurrency, false, func(i int) bool {
+			actual[i], errs[i] = c.verifyChecksum(common[i], byKey[common[i].key()])
+			return errs[i] == nil
+		}, func(int) {})
+
+		for i := range common {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			if actual[i] != "" {
+				drift.Entries = append(drift.Entries, DriftEntry{
+					Status:          DriftStatusChanged,
+					SnapshotPackage: common[i],
+					ActualChecksum:  actual[i],
+				})
+			} else {
+				drift.Same++
+			}
+		}
+	}
+
+	sort.SliceStable(drift.Entries, func(i, j int) bool {
+		return lessSnapshotPackage(drift.Entries[i].SnapshotPackage, drift.Entries[j].SnapshotPackage)
+	})
+
+	return drift, nil
+}
+
+// verifyChecksum returns the checksum of pkg if it differs from the checksum
+// recorded for expected, or an empty string if they are the same or no
+// checksum was recorded.
+func (c *Client) verifyChecksum(expected SnapshotPackage, pkg types.PackageFragment) (string, error) {
+	if expected.Checksum == "" {
+		return "", nil
+	}
+
+	details, err := c.GetPackageDetails(pkg)
+	if err != nil {
+		return "", fmt.Errorf("failed to get package details: %w", err)
+	}
+
+	algorithm, sum, err := checksumFor(*details, expected.Checksum)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare checksums of %s: %w", expected.Filename, err)
+	}
+	if sum == strings.TrimPrefix(expected.Checksum, algorithm+":") {
+		return "", nil
+	}
+	return algorithm + ":" + sum, nil
+}
+
+// checksumFor returns the checksum of details computed with the algorithm
+// of checksum, which is prefixed with its algorithm (i.e. sha256:2c26b4...).
+func checksumFor(details types.PackageDetails, checksum string) (string, string, error) {
+	algorithm, _, ok := strings.Cut(checksum, ":")
+	if !ok {
+		return "", "", fmt.Errorf("invalid checksum: %s", checksum)
+	}
+
+	sums := map[string]string{
+		"sha512": details.SHA512Sum,
+		"sha256": details.SHA256Sum,
+		"sha1":   details.SHA1Sum,
+		"md5":    details.MD5Sum,
+	}
+	sum, ok := sums[algorithm]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+	if sum == "" {
+		return "", "", fmt.Errorf("package has no %s checksum", algorithm)
+	}
+	return algorithm, sum, nil
+}
+
+func lessSnapshotPackage(a, b SnapshotPackage) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	if a.DistroVersion != b.DistroVersion {
+		return a.DistroVersion < b.DistroVersion
+	}
+	if a.Architecture != b.Architecture {
+		return a.Architecture < b.Architecture
+	}
+	if a.Type == b.Type {
+		if c := types.CompareVersionsLenient(types.VersionSchemeForPackageType(a.Type), a.Version, b.Version); c != 0 {
+			return c < 0
+		}
+	}
+	return a.Filename < b.Filename
+}
+
+type RestoreStatus string
+
+const (
+	// RestoreStatusRestored indicates that the package was promoted or
+	// pushed to the destination repository.
+	RestoreStatusRestored RestoreStatus = "restored"
+
+	// RestoreStatusPlanned indicates that the package would have been
+	// restored if the client was not in dry-run mode.
+	RestoreStatusPlanned RestoreStatus = "planned"
+
+	// RestoreStatusFailed indicates that restoring the package failed.
+	RestoreStatusFailed RestoreStatus = "failed"
+
+	// RestoreStatusSkipped indicates that the package was not restored
+	// because an earlier restore failed.
+	RestoreStatusSkipped RestoreStatus = "skipped"
+)
+
+type RestoreMethod string
+
+const (
+	// RestoreMethodPromote promotes the package from the source repository,
+	// which removes it from the source.
+	RestoreMethodPromote RestoreMethod = "promote"
+
+	// RestoreMethodPush downloads the package from the source repository
+	// and pushes it to the destination, leaving the source untouched.
+	RestoreMethodPush RestoreMethod = "push"
+)
+
+// RestoreResult describes the outcome of restoring a single package.
+type RestoreResult struct {
+	// Package is the package in the snapshot.
+	Package SnapshotPackage
+
+	// Status is the outcome of the restore.
+	Status RestoreStatus
+
+	// Err is the error that caused the restore to fail, if any.
+	Err error
+}
+
+func (r RestoreResult) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Package SnapshotPackage `json:"package"`
+		Status  RestoreStatus   `json:"status"`
+		Error   string          `json:"error,omitempty"`
+	}{
+		Package: r.Package,
+		Status:  r.Status,
+		Error:   errMsg,
+	})
+}
+
+type RestoreOptions struct {
+	// Method is how packages are restored. Defaults to
+	// RestoreMethodPromote.
+	Method RestoreMethod
+
+	// Concurrency is the maximum number of packages to restore at the same
+	// time. Defaults to 1.
+	Concurrency int
+
+	// ContinueOnError continues restoring the remaining packages after a
+	// restore fails. When false, packages that have not been restored by
+	// the time a restore fails are skipped.
+	ContinueOnError bool
+}
+
+// RestoreSnapshot restores the packages of snapshot that are missing from
+// dst by promoting or pushing them from src. A package is only restored if
+// it exists in src with the checksum recorded in the snapshot. Packages that
+// exist in dst are left as they are, even if their checksum has changed. A
+// result is returned for every missing package and, if any restore fails, a
+// *RestoreFailedError is returned along with the results.
+func (c *Client) RestoreSnapshot(snapshot *Snapshot, src Repo, dst Repo, opts RestoreOptions) ([]RestoreResult, error) {
+	if err := src.Validate(); err != nil {
+		return nil, fmt.Errorf("source repository validation failed: %w", err)
+	}
+	if opts.Method == "" {
+		opts.Method = RestoreMethodPromote
+	}
+	if opts.Method != RestoreMethodPromote && opts.Method != RestoreMethodPush {
+		return nil, fmt.Errorf("invalid restore method: %s", opts.Method)
+	}
+
+	drift, err := c.VerifySnapshot(dst, snapshot, VerifySnapshotOptions{SkipChecksums: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []SnapshotPackage
+	for _, entry := range drift.Entries {
+		if entry.Status == DriftStatusMissing {
+			missing = append(missing, entry.SnapshotPackage)
+		}
+	}
+
+	results := make([]RestoreResult, len(missing))
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	packages, err := c.ListPackages(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages of %s: %w", src, err)
+	}
+	byKey := make(map[string]types.PackageFragment, len(packages))
+	for _, pkg := range packages {
+		byKey[pkg.DistroVersion+"/"+pkg.Filename] = pkg
+	}
+
+	var packageTypes types.PackageTypes
+	if opts.Method == RestoreMethodPush {
+		packageTypes, err = c.GetDistributions()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch distributions: %w", err)
+		}
+	}
+
+	forEach(len(missing), opts.Concurrency, opts.ContinueOnError, func(i int) bool {
+		results[i] = RestoreResult{Package: missing[i]}
+
+		pkg, ok := byKey[missing[i].key()]
+		if !ok {
+			results[i].Status = RestoreStatusFailed
+			results[i].Err = fmt.Errorf("package not found in %s", src)
+			return false
+		}
+
+		if err := c.restorePackage(missing[i], src, pkg, dst, opts.Method, packageTypes); err != nil {
+			results[i].Status = RestoreStatusFailed
+			results[i].Err = err
+			return false
+		}
+
+		results[i].Status = RestoreStatusRestored
+		if c.DryRun() {
+			results[i].Status = RestoreStatusPlanned
+		}
+		return true
+	}, func(i int) {
+		results[i] = RestoreResult{
+			Package: missing[i],
+			Status:  RestoreStatusSkipped,
+		}
+	})
+
+	var failures []RestoreResult
+	for _, result := range results {
+		if result.Status == RestoreStatusFailed {
+			failures = append(failures, result)
+		}
+	}
+	if len(failures) > 0 {
+		return results, &RestoreFailedError{
+			Failures: failures,
+			Total:    len(results),
+		}
+	}
+
+	return results, nil
+}
+
+// restorePackage verifies that pkg has the checksum recorded for expected
+// and promotes or pushes it to dst. In dry-run mode, everything is resolved
+// but nothing is restored.
+func (c *Client) restorePackage(expected SnapshotPackage, src Repo, pkg types.PackageFragment, dst Repo, method RestoreMethod, packageTypes types.PackageTypes) error {
+	var details *types.PackageDetails
+	if expected.Checksum != "" || method == RestoreMethodPush {
+		var err error
+		details, err = c.GetPackageDetails(pkg)
+		if err != nil {
+			return fmt.Errorf("failed to get package details: %w", err)
+		}
+	}
+
+	if expected.Checksum != "" {
+		algorithm, sum, err := checksumFor(*details, expected.Checksum)
+		if err != nil {
+			return fmt.Errorf("failed to compare checksums: %w", err)
+		}
+		if want := strings.TrimPrefix(expected.Checksum, algorithm+":"); sum != want {
+			return &ChecksumError{
+				Filename:  pkg.Filename,
+				Algorithm: algorithm,
+				Expected:  want,
+				Actual:    sum,
+			}
+		}
+	}
+
+	var distroID int
+	if method == RestoreMethodPush {
+		distro, err := NewDistroFromString(pkg.DistroVersion)
+		if err != nil {
+			return fmt.Errorf("invalid distro: %w", err)
+		}
+		if _, ok := packageTypes[pkg.Type]; !ok {
+			return fmt.Errorf("unsupported package type: %s", pkg.Type)
+		}
+		distroID, err = types.GetDistroID(packageTypes[pkg.Type], pkg.Type, distro.Name, distro.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.DryRun() {
+		return nil
+	}
+
+	if method == RestoreMethodPush {
+		return transferPackage(c, *details, c, dst, distroID)
+	}
+
+	if pkg.PromoteURL == "" {
+		pkg.PromoteURL = buildPromoteURL(src, pkg)
+	}
+	return c.promote(pkg, dst)
+}
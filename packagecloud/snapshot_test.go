@@ -0,0 +1,132 @@
+package packagecloud
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	server := newChecksumServer(t, map[string][][3]string{
+		"ecorp/production": {
+			{"agent", "1.10.0", "ccc"},
+			{"agent", "1.0.0", "aaa"},
+			{"agent", "1.1.0", "bbb"},
+		},
+		"ecorp/dr": {
+			{"agent", "1.0.0", "aaa"},
+			{"agent", "1.1.0", "xxx"},
+			{"agent", "0.9.0", "ddd"},
+		},
+	})
+	client := server.client()
+
+	snapshot, err := client.ExportSnapshot(NewRepo("ecorp", "production"), SnapshotOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err = ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, pkg := range snapshot.Packages {
+		got = append(got, pkg.Version+" "+pkg.Checksum)
+	}
+	expected := []string{"1.0.0-1 sha256:aaa", "1.1.0-1 sha256:bbb", "1.10.0-1 sha256:ccc"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected packages %q, got %q", expected, got)
+	}
+
+	drift, err := client.VerifySnapshot(NewRepo("ecorp", "production"), snapshot, VerifySnapshotOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift.Entries) != 0 || drift.Same != 3 {
+		t.Errorf("expected no drift, got %+v", drift)
+	}
+
+	drift, err = client.VerifySnapshot(NewRepo("ecorp", "dr"), snapshot, VerifySnapshotOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, entry := range drift.Entries {
+		got = append(got, string(entry.Status)+" "+entry.Version+" "+entry.Checksum+" "+entry.ActualChecksum)
+	}
+	expected = []string{
+		"extra 0.9.0-1  ",
+		"changed 1.1.0-1 sha256:bbb sha256:xxx",
+		"missing 1.10.0-1 sha256:ccc ",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected drift %q, got %q", expected, got)
+	}
+	if drift.Same != 1 {
+		t.Errorf("expected 1 identical package, got %d", drift.Same)
+	}
+}
+
+func TestReadSnapshotVersion(t *testing.T) {
+	_, err := ReadSnapshot(bytes.NewBufferString(`{"version": 99}`))
+	if err == nil {
+		t.Fatal("expected an error for an unsupported snapshot version")
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	server := newChecksumServer(t, map[string][][3]string{
+		"ecorp/staging": {
+			{"agent", "1.0.0", "aaa"},
+			{"agent", "1.1.0", "xxx"},
+			{"agent", "1.10.0", "ccc"},
+		},
+		"ecorp/production": {
+			{"agent", "1.0.0", "aaa"},
+		},
+	})
+	client := server.client()
+
+	snapshot := &Snapshot{
+		Version:    SnapshotFormatVersion,
+		Repository: "ecorp/production",
+		Packages: []SnapshotPackage{
+			{Name: "agent", Type: "deb", Version: "1.0.0-1", DistroVersion: "ubuntu/jammy", Filename: "agent_1.0.0-1_amd64.deb", Checksum: "sha256:aaa"},
+			{Name: "agent", Type: "deb", Version: "1.1.0-1", DistroVersion: "ubuntu/jammy", Filename: "agent_1.1.0-1_amd64.deb", Checksum: "sha256:bbb"},
+			{Name: "agent", Type: "deb", Version: "1.10.0-1", DistroVersion: "ubuntu/jammy", Filename: "agent_1.10.0-1_amd64.deb", Checksum: "sha256:ccc"},
+			{Name: "agent", Type: "deb", Version: "2.0.0-1", DistroVersion: "ubuntu/jammy", Filename: "agent_2.0.0-1_amd64.deb", Checksum: "sha256:eee"},
+		},
+	}
+
+	results, err := client.RestoreSnapshot(snapshot, NewRepo("ecorp", "staging"), NewRepo("ecorp", "production"), RestoreOptions{ContinueOnError: true})
+	var failedErr *RestoreFailedError
+	if !errors.As(err, &failedErr) {
+		t.Fatalf("expected a *RestoreFailedError, got %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		got = append(got, result.Package.Version+" "+string(result.Status))
+	}
+	expected := []string{"1.1.0-1 failed", "1.10.0-1 restored", "2.0.0-1 failed"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected results %q, got %q", expected, got)
+	}
+
+	var checksumErr *ChecksumError
+	if !errors.As(results[0].Err, &checksumErr) {
+		t.Errorf("expected a *ChecksumError, got %v", results[0].Err)
+	}
+
+	expected = []string{"POST /api/v1/repos/ecorp/staging/ubuntu/jammy/agent_1.10.0-1_amd64.deb/promote.json?destination=ecorp%2Fproduction"}
+	if got := server.changes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected requests %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,235 @@
+package packagecloud
+
+import (
+	"encoding/json"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+type SyncAction string
+
+const (
+	// SyncActionPromote promotes a package that is missing from the
+	// destination repository.
+	SyncActionPromote SyncAction = "promote"
+
+	// SyncActionDelete deletes a package that only exists in the
+	// destination repository.
+	SyncActionDelete SyncAction = "delete"
+)
+
+type SyncStatus string
+
+const (
+	// SyncStatusPromoted indicates that the package was promoted.
+	SyncStatusPromoted SyncStatus = "promoted"
+
+	// SyncStatusDeleted indicates that the package was deleted.
+	SyncStatusDeleted SyncStatus = "deleted"
+
+	// SyncStatusPlanned indicates that the action would have been performed
+	// if the client was not in dry-run mode.
+	SyncStatusPlanned SyncStatus = "planned"
+
+	// SyncStatusFailed indicates that the action failed.
+	SyncStatusFailed SyncStatus = "failed"
+
+	// SyncStatusExists indicates that the package was not promoted because
+	// an identical package already exists in the destination repository.
+	SyncStatusExists SyncStatus = "exists"
+
+	// SyncStatusSkipped indicates that the action was not attempted because
+	// an earlier action failed.
+	SyncStatusSkipped SyncStatus = "skipped"
+)
+
+// SyncPlan lists what a sync would change.
+type SyncPlan struct {
+	Source      Repo
+	Destination Repo
+
+	// Promote are the packages of the source repository that are missing
+	// from the destination repository.
+	Promote types.PackageFragments
+
+	// Delete are the packages that only exist in the destination
+	// repository. It is only set when planning with deleteExtraneous.
+	Delete types.PackageFragments
+
+	// InSync is the number of packages that already exist in both
+	// repositories.
+	InSync int
+}
+
+// PlanSync compares the packages matching search in the source and
+// destination repositories and returns the packages that have to be
+// promoted for the destination to contain every package of the source and,
+// if deleteExtraneous is set, the packages of the destination that have to
+// be deleted for it to contain nothing else. The repository in search is
+// ignored. Packages that exist in both repositories are left as they are,
+// whatever their checksum.
+func (c *Client) PlanSync(src Repo, dst Repo, search SearchOptions, deleteExtraneous bool) (*SyncPlan, error) {
+	diff, err := c.Diff(src, dst, search, DiffOptions{SkipChecksums: true})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &SyncPlan{
+		Source:      src,
+		Destination: dst,
+		Promote:     types.PackageFragments{},
+		Delete:      types.PackageFragments{},
+		InSync:      diff.Same,
+	}
+
+	for _, entry := range diff.Entries {
+		switch entry.Status {
+		case DiffStatusLeftOnly:
+			pkg := entry.Package
+			if pkg.PromoteURL == "" {
+				pkg.PromoteURL = buildPromoteURL(src, pkg)
+			}
+			plan.Promote = append(plan.Promote, pkg)
+		case DiffStatusRightOnly:
+			if deleteExtraneous {
+				plan.Delete = append(plan.Delete, entry.Package)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// SyncResult describes the outcome of a single action of a sync.
+type SyncResult struct {
+	// Action is what was done to the package.
+	Action SyncAction
+
+	// Package is the package the action is for.
+	Package types.PackageFragment
+
+	// Status is the outcome of the action.
+	Status SyncStatus
+
+	// Err is the error that caused the action to fail, if any.
+	Err error
+}
+
+func (r SyncResult) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Action  SyncAction            `json:"action"`
+		Package types.PackageFragment `json:"package"`
+		Status  SyncStatus            `json:"status"`
+		Error   string                `json:"error,omitempty"`
+	}{
+		Action:  r.Action,
+		Package: r.Package,
+		Status:  r.Status,
+		Error:   errMsg,
+	})
+}
+
+type SyncOptions struct {
+	// Concurrency is the maximum number of packages to promote or delete at
+	// the same time. Defaults to 1.
+	Concurrency int
+
+	// ContinueOnError continues with the remaining packages after an action
+	// fails. When false, the actions that have not been performed by the
+	// time one fails are skipped.
+	ContinueOnError bool
+}
+
+// ExecuteSync promotes and then deletes the packages of plan. A result is
+// returned for every package, promotions first, in the same order as in
+// plan. In dry-run mode, nothing is changed. If any action fails, a
+// *SyncFailedError is returned along with the results.
+func (c *Client) ExecuteSync(plan *SyncPlan, opts SyncOptions) ([]SyncResult, error) {
+	results := make([]SyncResult, 0, len(plan.Promote)+len(plan.Delete))
+
+	promoteResults, promoteErr := c.PromotePackages(plan.Source, plan.Destination, plan.Promote, PromoteOptions{
+		Concurrency:     opts.Concurrency,
+		ContinueOnError: opts.ContinueOnError,
+	})
+	if promoteErr != nil && promoteResults == nil {
+		return nil, promoteErr
+	}
+	for _, result := range promoteResults {
+		results = append(results, SyncResult{
+			Action:  SyncActionPromote,
+			Package: result.Package,
+			Status:  syncStatusFromPromote(result.Status),
+			Err:     result.Err,
+		})
+	}
+
+	deleteResults := make([]SyncResult, len(plan.Delete))
+	skip := func(i int) {
+		deleteResults[i] = SyncResult{
+			Action:  SyncActionDelete,
+			Package: plan.Delete[i],
+			Status:  SyncStatusSkipped,
+		}
+	}
+
+	if promoteErr != nil && !opts.ContinueOnError {
+		for i := range plan.Delete {
+			skip(i)
+		}
+	} else {
+		forEach(len(plan.Delete), opts.Concurrency, opts.ContinueOnError, func(i int) bool {
+			pkg := plan.Delete[i]
+			deleteResults[i] = SyncResult{Action: SyncActionDelete, Package: pkg}
+
+			if c.DryRun() {
+				deleteResults[i].Status = SyncStatusPlanned
+				return true
+			}
+
+			if err := c.DestroyPackage(pkg); err != nil {
+				deleteResults[i].Status = SyncStatusFailed
+				deleteResults[i].Err = err
+				return false
+			}
+
+			deleteResults[i].Status = SyncStatusDeleted
+			return true
+		}, skip)
+	}
+	results = append(results, deleteResults...)
+
+	var failures []SyncResult
+	for _, result := range results {
+		if result.Status == SyncStatusFailed {
+			failures = append(failures, result)
+		}
+	}
+	if len(failures) > 0 {
+		return results, &SyncFailedError{
+			Failures: failures,
+			Total:    len(results),
+		}
+	}
+
+	return results, nil
+}
+
+func syncStatusFromPromote(status PromoteStatus) SyncStatus {
+	switch status {
+	case PromoteStatusPromoted:
+		return SyncStatusPromoted
+	case PromoteStatusPlanned:
+		return SyncStatusPlanned
+	case PromoteStatusFailed:
+		return SyncStatusFailed
+	case PromoteStatusExists:
+		return SyncStatusExists
+	default:
+		return SyncStatusSkipped
+	}
+}
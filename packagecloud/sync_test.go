@@ -0,0 +1,108 @@
+package packagecloud
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+// newSyncServer returns a fake server for syncing ecorp/staging to ecorp/qa.
+func newSyncServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	return newFakeServer(t, map[string]types.PackageFragments{
+		"ecorp/staging": {
+			fakePackage("ecorp/staging", "ubuntu/jammy", "amd64", "agent", "1.0"),
+			fakePackage("ecorp/staging", "ubuntu/jammy", "amd64", "agent", "1.1"),
+		},
+		"ecorp/qa": {
+			fakePackage("ecorp/qa", "ubuntu/jammy", "amd64", "agent", "1.0"),
+			fakePackage("ecorp/qa", "ubuntu/jammy", "amd64", "agent", "0.9"),
+			fakePackage("ecorp/qa", "ubuntu/jammy", "amd64", "agent", "0.8"),
+		},
+	})
+}
+
+func TestSync(t *testing.T) {
+	server := newSyncServer(t)
+	client := server.client()
+
+	plan, err := client.PlanSync(NewRepo("ecorp", "staging"), NewRepo("ecorp", "qa"), SearchOptions{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Promote) != 1 || len(plan.Delete) != 2 || plan.InSync != 1 {
+		t.Fatalf("expected 1 package to promote, 2 to delete and 1 in sync, got %+v", plan)
+	}
+
+	results, err := client.ExecuteSync(plan, SyncOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Status != SyncStatusPromoted && result.Status != SyncStatusDeleted {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+
+	expected := []string{
+		"DELETE /api/v1/repos/ecorp/qa/ubuntu/jammy/agent_0.8-1_amd64.deb",
+		"DELETE /api/v1/repos/ecorp/qa/ubuntu/jammy/agent_0.9-1_amd64.deb",
+		"POST /api/v1/repos/ecorp/staging/ubuntu/jammy/agent_1.1-1_amd64.deb/promote.json?destination=ecorp%2Fqa",
+	}
+	if got := server.changes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected requests %v, got %v", expected, got)
+	}
+}
+
+func TestSyncKeepsExtraneous(t *testing.T) {
+	client := newSyncServer(t).client()
+
+	plan, err := client.PlanSync(NewRepo("ecorp", "staging"), NewRepo("ecorp", "qa"), SearchOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Delete) != 0 {
+		t.Errorf("expected nothing to delete, got %v", plan.Delete)
+	}
+}
+
+func TestSyncDeleteFailure(t *testing.T) {
+	server := newSyncServer(t)
+	server.fail("/api/v1/repos/ecorp/qa/ubuntu/jammy/agent_0.9-1_amd64.deb")
+	client := server.client()
+
+	plan, err := client.PlanSync(NewRepo("ecorp", "staging"), NewRepo("ecorp", "qa"), SearchOptions{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.ExecuteSync(plan, SyncOptions{ContinueOnError: true})
+	var failedErr *SyncFailedError
+	if !errors.As(err, &failedErr) {
+		t.Fatalf("expected a *SyncFailedError, got %v", err)
+	}
+	if len(failedErr.Failures) != 1 || failedErr.Failures[0].Action != SyncActionDelete {
+		t.Errorf("expected a single failed deletion, got %+v", failedErr.Failures)
+	}
+	if len(results) != 3 {
+		t.Errorf("expected 3 results, got %d", len(results))
+	}
+}
+
+func TestSyncStatusFromPromote(t *testing.T) {
+	tests := map[PromoteStatus]SyncStatus{
+		PromoteStatusPromoted: SyncStatusPromoted,
+		PromoteStatusPlanned:  SyncStatusPlanned,
+		PromoteStatusFailed:   SyncStatusFailed,
+		PromoteStatusExists:   SyncStatusExists,
+		PromoteStatusSkipped:  SyncStatusSkipped,
+	}
+	for status, expected := range tests {
+		if got := syncStatusFromPromote(status); got != expected {
+			t.Errorf("expected %s to map to %s, got %s", status, expected, got)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package packagecloud
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 func isEmptyString(s string) bool {
@@ -15,3 +17,38 @@ func bugPanic(msg string) {
 	// due to a logic error or incorrect usage.
 	panic(fmt.Sprintf("BUG: %s", msg))
 }
+
+// forEach calls fn for every index in [0, n) using up to concurrency
+// goroutines. Once fn returns false, the remaining indexes are passed to skip
+// instead, unless continueOnError is true.
+func forEach(n int, concurrency int, continueOnError bool, fn func(int) bool, skip func(int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+	failed := atomic.Bool{}
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if failed.Load() && !continueOnError {
+					skip(j)
+					continue
+				}
+				if !fn(j) {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
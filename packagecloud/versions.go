@@ -30,6 +30,20 @@ type ListVersionsOptions struct {
 	// PerPage is the number of packages to return from the results set. If
 	// nothing passed the default is 30.
 	PerPage string
+
+	// Scheme is the version scheme used to order versions. When nil, the
+	// scheme is chosen from the type of each package (i.e. dpkg for debs,
+	// rpm for rpms and semver for everything else).
+	Scheme types.VersionScheme
+
+	// Constraint limits the versions considered by LatestVersion,
+	// PreviousVersion, NthVersion and VersionMatrix to those matching it
+	// (i.e. "~1.4", "<2.0.0"). See types.VersionConstraint for the syntax.
+	Constraint string
+
+	// IncludePrereleases includes pre-release versions in LatestVersion,
+	// PreviousVersion, NthVersion, VersionMatrix and FindPackagesByVersion.
+	IncludePrereleases bool
 }
 
 func (o ListVersionsOptions) SearchOptions() SearchOptions {
@@ -50,56 +64,235 @@ func (o ListVersionsOptions) Validate() error {
 	if o.PackageName == "" {
 		return fmt.Errorf("package name cannot be empty")
 	}
+	if _, err := o.versionConstraint(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// versionConstraint returns the parsed Constraint, or nil if it is empty.
+func (o ListVersionsOptions) versionConstraint() (*types.VersionConstraint, error) {
+	if o.Constraint == "" {
+		return nil, nil
+	}
+	return types.ParseVersionConstraint(o.Constraint)
+}
+
+// ListVersions returns every version of the package given in options. The
+// versions of each package type are keyed and ordered with the scheme of the
+// type, unless options sets a scheme.
 func (c *Client) ListVersions(options ListVersionsOptions) (types.PackageVersions, error) {
-	versions := types.PackageVersions{}
+	packages, err := c.ListVersionPackages(options)
+	if err != nil {
+		return types.PackageVersions{}, err
+	}
+
+	versions := types.NewPackageVersions(options.Scheme)
+	for _, pkg := range packages {
+		versions.Add(pkg)
+	}
+
+	return versions, nil
+}
+
+// ListVersionPackages returns every package with the name given in options.
+func (c *Client) ListVersionPackages(options ListVersionsOptions) (types.PackageFragments, error) {
+	var packages types.PackageFragments
 	mu := &sync.RWMutex{}
 
-	callback := func(streamPackages types.PackageFragments) {
+	if err := c.listPackagesStream(options, func(streamPackages types.PackageFragments) {
 		mu.Lock()
+		packages = append(packages, streamPackages...)
+		mu.Unlock()
+	}); err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+// FindPackagesByVersion returns every package with the name given in options
+// whose version satisfies the given constraint (i.e. ">=2.3.0 <2.4.0"). See
+// types.VersionConstraint for the syntax. Versions are compared with the
+// scheme given in options or, if none is set, the scheme of each package's
+// type. Packages with versions that cannot be parsed never match.
+func (c *Client) FindPackagesByVersion(options ListVersionsOptions, constraint string) (types.PackageFragments, error) {
+	constraints, err := types.ParseVersionConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := c.ListVersionPackages(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches types.PackageFragments
+	for _, pkg := range packages {
+		scheme := options.Scheme
+		if scheme == nil {
+			scheme = types.VersionSchemeForPackageType(pkg.Type)
+		}
+
+		version := types.PackageVersionKey(scheme, pkg.Epoch, pkg.Version, pkg.Release)
+		if !options.IncludePrereleases && scheme.IsPrerelease(version) {
+			continue
+		}
+
+		ok, err := constraints.Check(scheme, version)
+		if err != nil {
+			continue
+		}
+		if ok {
+			matches = append(matches, pkg)
+		}
+	}
+
+	return matches, nil
+}
+
+// listPackagesStream calls fn with each page of packages with the name given
+// in options.
+func (c *Client) listPackagesStream(options ListVersionsOptions, fn func(types.PackageFragments)) error {
+	callback := func(streamPackages types.PackageFragments) {
+		var packages types.PackageFragments
 		for _, pkg := range streamPackages {
 			if pkg.Name == options.PackageName {
-				key := pkg.Version
-				if pkg.Type == "rpm" {
-					key = fmt.Sprintf("%s-%s", key, pkg.Release)
-				}
-				versions[key]++
+				packages = append(packages, pkg)
 			}
 		}
-		mu.Unlock()
+		fn(packages)
 	}
 
 	if options.Filter != "" || options.Dist != "" || options.Arch != "" {
-		searchOptions := options.SearchOptions()
+		return c.SearchStream(options.SearchOptions(), callback)
+	}
+	return c.ListPackagesStream(options.Repo, callback)
+}
 
-		if err := c.SearchStream(searchOptions, callback); err != nil {
-			return nil, err
-		}
-	} else {
-		if err := c.ListPackagesStream(options.Repo, callback); err != nil {
-			return nil, err
-		}
+// SelectVersions returns the versions of the package given in options that
+// match options.Constraint, excluding pre-releases unless
+// options.IncludePrereleases is set.
+func (c *Client) SelectVersions(options ListVersionsOptions) (types.PackageVersions, error) {
+	constraint, err := options.versionConstraint()
+	if err != nil {
+		return types.PackageVersions{}, err
 	}
 
-	return versions, nil
+	versions, err := c.ListVersions(options)
+	if err != nil {
+		return types.PackageVersions{}, err
+	}
+
+	return versions.Select(constraint, options.IncludePrereleases)
 }
 
 func (c *Client) LatestVersion(options ListVersionsOptions) (string, error) {
-	versions, err := c.ListVersions(options)
+	return c.NthVersion(options, 0)
+}
+
+func (c *Client) PreviousVersion(options ListVersionsOptions) (string, error) {
+	return c.NthVersion(options, 1)
+}
+
+// NthVersion returns the nth newest version of the package given in
+// options, where 0 is the latest version.
+func (c *Client) NthVersion(options ListVersionsOptions, n int) (string, error) {
+	versions, err := c.SelectVersions(options)
 	if err != nil {
 		return "", err
 	}
 
-	return versions.LatestVersion()
+	return versions.NthVersion(n)
 }
 
-func (c *Client) PreviousVersion(options ListVersionsOptions) (string, error) {
-	versions, err := c.ListVersions(options)
+// VersionMatrix returns the number of packages with each version of the
+// package given in options for each distro and architecture.
+func (c *Client) VersionMatrix(options ListVersionsOptions) (*types.VersionMatrix, error) {
+	constraint, err := options.versionConstraint()
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := c.ListVersionPackages(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected types.PackageFragments
+	for _, pkg := range packages {
+		scheme := options.Scheme
+		if scheme == nil {
+			scheme = types.VersionSchemeForPackageType(pkg.Type)
+		}
+
+		version := types.PackageVersionKey(scheme, pkg.Epoch, pkg.Version, pkg.Release)
+		if !options.IncludePrereleases && scheme.IsPrerelease(version) {
+			continue
+		}
+		if constraint != nil {
+			ok, err := constraint.Check(scheme, version)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		selected = append(selected, pkg)
+	}
+
+	return types.NewVersionMatrix(options.Scheme, selected)
+}
+
+// NextVersion returns the version after the latest version of the package
+// given in options, including pre-releases, when incrementing the given
+// part. See types.BumpVersion.
+func (c *Client) NextVersion(options ListVersionsOptions, bump types.VersionBump, preID string) (string, error) {
+	options.IncludePrereleases = true
+
+	versions, err := c.SelectVersions(options)
+	if err != nil {
+		return "", err
+	}
+
+	latest, err := versions.LatestVersion()
 	if err != nil {
 		return "", err
 	}
 
-	return versions.PreviousVersion()
+	return types.BumpVersion(versions.SchemeOf(latest), latest, bump, preID)
+}
+
+// CheckNextVersion returns a *VersionNotNewerError if candidate is not
+// strictly newer than the latest version of the package given in options,
+// including pre-releases. Any candidate is accepted if no versions exist.
+func (c *Client) CheckNextVersion(options ListVersionsOptions, candidate string) error {
+	options.IncludePrereleases = true
+
+	versions, err := c.SelectVersions(options)
+	if err != nil {
+		return err
+	}
+	if len(versions.Versions) == 0 {
+		return nil
+	}
+
+	latest, err := versions.LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	cmp, err := versions.SchemeOf(latest).Compare(candidate, latest)
+	if err != nil {
+		return err
+	}
+	if cmp <= 0 {
+		return &VersionNotNewerError{
+			Candidate: candidate,
+			Latest:    latest,
+		}
+	}
+
+	return nil
 }
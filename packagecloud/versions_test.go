@@ -0,0 +1,92 @@
+package packagecloud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+func TestListVersionsDeb(t *testing.T) {
+	agent := func(version, release string) types.PackageFragment {
+		pkg := fakePackage("ecorp/staging", "ubuntu/jammy", "amd64", "agent", version)
+		pkg.Release = release
+		return pkg
+	}
+	server := newFakeServer(t, map[string]types.PackageFragments{
+		"ecorp/staging": {
+			agent("1.0", "1"),
+			agent("1.0", "2"),
+			agent("1.0", "10"),
+			agent("1.0a", "1"),
+		},
+	})
+	client := server.client()
+	options := ListVersionsOptions{Repo: NewRepo("ecorp", "staging"), PackageName: "agent"}
+
+	versions, err := client.ListVersions(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := versions.ReverseSorted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"1.0a-1", "1.0-10", "1.0-2", "1.0-1"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected versions %v, got %v", expected, got)
+	}
+
+	options.Constraint = "<1.0-10"
+	latest, err := client.LatestVersion(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != "1.0-2" {
+		t.Errorf("expected latest version 1.0-2, got %s", latest)
+	}
+}
+
+func TestListVersionsMixedTypes(t *testing.T) {
+	deb := func(version string) types.PackageFragment {
+		return fakePackage("ecorp/staging", "ubuntu/jammy", "amd64", "agent", version)
+	}
+	rpm := func(version string) types.PackageFragment {
+		pkg := fakePackage("ecorp/staging", "el/9", "x86_64", "agent", version)
+		pkg.Type = "rpm"
+		pkg.Filename = "agent-" + version + "-1.x86_64.rpm"
+		return pkg
+	}
+	server := newFakeServer(t, map[string]types.PackageFragments{
+		"ecorp/staging": {
+			deb("1.0"),
+			deb("1.0~rc1"),
+			rpm("1.1"),
+			rpm("1.0~rc1"),
+			rpm("0.9"),
+		},
+	})
+	client := server.client()
+	options := ListVersionsOptions{Repo: NewRepo("ecorp", "staging"), PackageName: "agent"}
+
+	versions, err := client.ListVersions(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := versions.ReverseSorted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"1.1-1", "1.0-1", "1.0~rc1-1", "0.9-1"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected versions %v, got %v", expected, got)
+	}
+
+	latest, err := client.LatestVersion(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != "1.1-1" {
+		t.Errorf("expected latest version 1.1-1, got %s", latest)
+	}
+}
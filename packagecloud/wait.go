@@ -0,0 +1,165 @@
+package packagecloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+const (
+	defaultWaitInitialInterval = 2 * time.Second
+	defaultWaitMaxInterval     = 30 * time.Second
+	defaultWaitMultiplier      = 2.0
+	defaultWaitTimeout         = 2 * time.Minute
+)
+
+type WaitOptions struct {
+	// Filenames restricts the wait to packages with the given filenames. A
+	// filename that does not (yet) appear in the search results is treated
+	// as not indexed. When empty, every package matching the search filter
+	// must be indexed.
+	Filenames []string
+
+	// InitialInterval is the delay before the first retry. Defaults to 2s.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between retries. Defaults to 30s.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor the delay grows by after each retry. Defaults
+	// to 2.
+	Multiplier float64
+
+	// Timeout is the maximum amount of time to wait overall. Defaults to 2m.
+	Timeout time.Duration
+
+	// Clock is used to measure time and to sleep between retries. Defaults
+	// to the system clock.
+	Clock Clock
+
+	// OnPending, if set, is called each time the search results still
+	// contain packages that have not been indexed, with the filenames of
+	// those packages and the delay before the next attempt.
+	OnPending func(pending []string, next time.Duration)
+}
+
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = defaultWaitInitialInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultWaitMaxInterval
+	}
+	if o.MaxInterval < o.InitialInterval {
+		o.MaxInterval = o.InitialInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = defaultWaitMultiplier
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultWaitTimeout
+	}
+	if o.Clock == nil {
+		o.Clock = realClock{}
+	}
+	return o
+}
+
+// WaitForIndexed polls the search API until every package matching filter
+// (or, when opts.Filenames is set, every named package) has been indexed by
+// packagecloud. The delay between polls grows exponentially up to
+// opts.MaxInterval. The matching packages are returned once they have all
+// been indexed.
+func (c *Client) WaitForIndexed(ctx context.Context, filter SearchOptions, opts WaitOptions) (types.PackageFragments, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts = opts.withDefaults()
+	deadline := opts.Clock.Now().Add(opts.Timeout)
+	interval := opts.InitialInterval
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		packages, err := c.Search(filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve search results: %w", err)
+		}
+
+		matched, pending := pendingPackages(packages, opts.Filenames)
+		if len(pending) == 0 {
+			return matched, nil
+		}
+
+		now := opts.Clock.Now()
+		if !now.Before(deadline) {
+			return nil, &WaitTimeoutError{
+				Timeout: opts.Timeout,
+				Pending: pending,
+			}
+		}
+
+		delay := interval
+		if remaining := deadline.Sub(now); delay > remaining {
+			delay = remaining
+		}
+
+		if opts.OnPending != nil {
+			opts.OnPending(pending, delay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-opts.Clock.After(delay):
+		}
+
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// pendingPackages returns the packages the wait applies to along with the
+// sorted filenames of those that have not been indexed yet.
+func pendingPackages(packages types.PackageFragments, filenames []string) (types.PackageFragments, []string) {
+	var matched types.PackageFragments
+	pending := map[string]struct{}{}
+
+	if len(filenames) == 0 {
+		matched = packages
+	} else {
+		wanted := map[string]struct{}{}
+		for _, filename := range filenames {
+			wanted[filename] = struct{}{}
+			pending[filename] = struct{}{}
+		}
+		for _, pkg := range packages {
+			if _, ok := wanted[pkg.Filename]; ok {
+				matched = append(matched, pkg)
+				delete(pending, pkg.Filename)
+			}
+		}
+	}
+
+	for _, pkg := range matched {
+		if !pkg.Indexed {
+			pending[pkg.Filename] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return matched, names
+}
@@ -0,0 +1,127 @@
+package packagecloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestWaitForIndexedBacksOff(t *testing.T) {
+	pending := types.PackageFragments{{Filename: "a.deb"}, {Filename: "b.deb", Indexed: true}}
+	indexed := types.PackageFragments{{Filename: "a.deb", Indexed: true}, {Filename: "b.deb", Indexed: true}}
+	server := newFakeServer(t, nil)
+	server.sequence("user/repo", pending, pending, pending, indexed)
+	client := server.client()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	packages, err := client.WaitForIndexed(context.Background(), SearchOptions{
+		RepoUser: "user",
+		RepoName: "repo",
+		Query:    "deb",
+	}, WaitOptions{
+		InitialInterval: time.Second,
+		MaxInterval:     3 * time.Second,
+		Clock:           clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 2 {
+		t.Errorf("expected 2 packages, got %d", len(packages))
+	}
+	if server.searches("user/repo") != 4 {
+		t.Errorf("expected 4 search requests, got %d", server.searches("user/repo"))
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(clock.sleeps) != len(expected) {
+		t.Fatalf("expected sleeps %v, got %v", expected, clock.sleeps)
+	}
+	for i := range expected {
+		if clock.sleeps[i] != expected[i] {
+			t.Errorf("expected sleeps %v, got %v", expected, clock.sleeps)
+			break
+		}
+	}
+}
+
+func TestWaitForIndexedFilenames(t *testing.T) {
+	first := types.PackageFragments{{Filename: "old.deb", DistroVersion: "ubuntu/jammy"}}
+	second := types.PackageFragments{
+		{Filename: "old.deb", DistroVersion: "ubuntu/jammy"},
+		{Filename: "new.deb", DistroVersion: "ubuntu/jammy", Indexed: true},
+	}
+	server := newFakeServer(t, nil)
+	server.sequence("user/repo", first, second)
+	client := server.client()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	var reported [][]string
+	packages, err := client.WaitForIndexed(context.Background(), SearchOptions{
+		RepoUser: "user",
+		RepoName: "repo",
+		Dist:     "ubuntu/jammy",
+	}, WaitOptions{
+		Filenames: []string{"new.deb"},
+		Clock:     clock,
+		OnPending: func(pending []string, next time.Duration) {
+			reported = append(reported, pending)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 1 || packages[0].Filename != "new.deb" {
+		t.Errorf("expected only new.deb to be returned, got %v", packages)
+	}
+	if len(reported) != 1 || len(reported[0]) != 1 || reported[0][0] != "new.deb" {
+		t.Errorf("expected new.deb to be reported as pending once, got %v", reported)
+	}
+}
+
+func TestWaitForIndexedTimeout(t *testing.T) {
+	server := newFakeServer(t, map[string]types.PackageFragments{"user/repo": {{Filename: "a.deb"}}})
+	client := server.client()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	_, err := client.WaitForIndexed(context.Background(), SearchOptions{
+		RepoUser: "user",
+		RepoName: "repo",
+		Query:    "a",
+	}, WaitOptions{
+		InitialInterval: 4 * time.Second,
+		Timeout:         10 * time.Second,
+		Clock:           clock,
+	})
+
+	var timeoutErr *WaitTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected WaitTimeoutError, got %v", err)
+	}
+	if len(timeoutErr.Pending) != 1 || timeoutErr.Pending[0] != "a.deb" {
+		t.Errorf("expected a.deb to be pending, got %v", timeoutErr.Pending)
+	}
+	if elapsed := clock.now.Sub(time.Unix(0, 0)); elapsed != 10*time.Second {
+		t.Errorf("expected to wait exactly 10s, waited %s", elapsed)
+	}
+}
@@ -0,0 +1,300 @@
+package packagecloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+const (
+	defaultWatchInterval = 30 * time.Second
+
+	watchStateVersion = 1
+)
+
+type WatchEventType string
+
+const (
+	// WatchEventNew is emitted the first time a package is seen.
+	WatchEventNew WatchEventType = "new"
+
+	// WatchEventIndexed is emitted when a package that was seen before it
+	// was indexed has been indexed.
+	WatchEventIndexed WatchEventType = "indexed"
+)
+
+// WatchEvent describes a change to a watched repository.
+type WatchEvent struct {
+	// Type is the type of event.
+	Type WatchEventType `json:"type"`
+
+	// Time is when the change was noticed.
+	Time time.Time `json:"time"`
+
+	// Repository is the repository of the package (user/repo).
+	Repository string `json:"repository"`
+
+	// Package is the package the event is about.
+	Package types.PackageFragment `json:"package"`
+}
+
+// WatchState is the set of packages seen by a watch. It is persisted
+// between runs so that a restarted watch only reports changes that happened
+// since it last polled.
+type WatchState struct {
+	Version int `json:"version"`
+
+	// Packages maps the key of each package seen to whether or not it was
+	// indexed when it was last seen.
+	Packages map[string]bool `json:"packages"`
+
+	// fresh is true when the state was not loaded from a previous run.
+	fresh bool
+}
+
+// NewWatchState returns an empty state.
+func NewWatchState() *WatchState {
+	return &WatchState{
+		Version:  watchStateVersion,
+		Packages: map[string]bool{},
+		fresh:    true,
+	}
+}
+
+// LoadWatchState reads the state saved at path. An empty state is returned
+// if the file does not exist.
+func LoadWatchState(path string) (*WatchState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewWatchState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch state: %w", err)
+	}
+
+	state := &WatchState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse watch state %s: %w", path, err)
+	}
+	if state.Version != watchStateVersion {
+		return nil, fmt.Errorf("unsupported watch state version %d in %s", state.Version, path)
+	}
+	if state.Packages == nil {
+		state.Packages = map[string]bool{}
+	}
+
+	return state, nil
+}
+
+// Save writes the state to path. The file is replaced atomically so that an
+// interrupted save does not lose the previous state.
+func (s *WatchState) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+
+	s.fresh = false
+	return nil
+}
+
+// watchKey returns the key a package is tracked by. Filenames are only
+// unique within a distro version.
+func watchKey(repo Repo, pkg types.PackageFragment) string {
+	return fmt.Sprintf("%s/%s/%s", repo, pkg.DistroVersion, pkg.Filename)
+}
+
+// Changes returns the events for packages that are new or have been indexed
+// since the state was last updated, ordered by upload time.
+func (s *WatchState) Changes(repo Repo, packages types.PackageFragments, now time.Time) []WatchEvent {
+	var events []WatchEvent
+	for _, pkg := range packages {
+		indexed, seen := s.Packages[watchKey(repo, pkg)]
+		switch {
+		case !seen:
+			events = append(events, WatchEvent{Type: WatchEventNew, Time: now, Repository: repo.String(), Package: pkg})
+		case !indexed && pkg.Indexed:
+			events = append(events, WatchEvent{Type: WatchEventIndexed, Time: now, Repository: repo.String(), Package: pkg})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		createdI, _ := events[i].Package.CreatedTime()
+		createdJ, _ := events[j].Package.CreatedTime()
+		return createdI.Before(createdJ)
+	})
+
+	return events
+}
+
+// Record marks a package as seen.
+func (s *WatchState) Record(repo Repo, pkg types.PackageFragment) {
+	s.Packages[watchKey(repo, pkg)] = pkg.Indexed
+}
+
+// Forget removes the packages of repo that are not in packages, so that a
+// package that is deleted and uploaded again is reported again.
+func (s *WatchState) Forget(repo Repo, packages types.PackageFragments) {
+	current := map[string]struct{}{}
+	for _, pkg := range packages {
+		current[watchKey(repo, pkg)] = struct{}{}
+	}
+
+	prefix := repo.String() + "/"
+	for key := range s.Packages {
+		if _, ok := current[key]; !ok && strings.HasPrefix(key, prefix) {
+			delete(s.Packages, key)
+		}
+	}
+}
+
+type WatchOptions struct {
+	// Interval is the delay between polls. Defaults to 30s.
+	Interval time.Duration
+
+	// State is the set of packages already seen. Defaults to an empty
+	// state.
+	State *WatchState
+
+	// StatePath, if set, is where the state is saved after each poll.
+	StatePath string
+
+	// EmitExisting reports the packages found by the first poll of a fresh
+	// state as new. Otherwise they are recorded without being reported.
+	EmitExisting bool
+
+	// Once stops the watch after a single poll.
+	Once bool
+
+	// Clock is used to sleep between polls. Defaults to the system clock.
+	Clock Clock
+
+	// OnEvent is called for each event. Returning an error stops the watch
+	// with the error wrapped in a *WatchEventError; the event is not
+	// recorded and will be reported again by the next run.
+	OnEvent func(WatchEvent) error
+
+	// OnError, if set, is called when a poll fails and the watch carries
+	// on with the next poll. Otherwise the watch stops with the error.
+	OnError func(error)
+}
+
+func (o WatchOptions) withDefaults() WatchOptions {
+	if o.Interval <= 0 {
+		o.Interval = defaultWatchInterval
+	}
+	if o.State == nil {
+		o.State = NewWatchState()
+	}
+	if o.Clock == nil {
+		o.Clock = realClock{}
+	}
+	return o
+}
+
+// Watch polls the search API for packages matching options until ctx is
+// done, calling opts.OnEvent for each package that is uploaded or indexed.
+func (c *Client) Watch(ctx context.Context, options SearchOptions, opts WatchOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	opts = opts.withDefaults()
+	repo := NewRepo(options.RepoUser, options.RepoName)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := c.watchPoll(repo, options, opts); err != nil {
+			var eventErr *WatchEventError
+			if errors.As(err, &eventErr) || opts.OnError == nil {
+				return err
+			}
+			opts.OnError(err)
+		}
+
+		if opts.Once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-opts.Clock.After(opts.Interval):
+		}
+	}
+}
+
+// watchPoll searches for packages once, reports the changes since the last
+// poll and saves the state. An error returned by opts.OnEvent is wrapped in
+// a *WatchEventError.
+func (c *Client) watchPoll(repo Repo, options SearchOptions, opts WatchOptions) error {
+	var packages types.PackageFragments
+	mu := &sync.Mutex{}
+
+	if err := c.SearchStream(options, func(page types.PackageFragments) {
+		mu.Lock()
+		packages = append(packages, page...)
+		mu.Unlock()
+	}); err != nil {
+		return fmt.Errorf("failed to retrieve search results: %w", err)
+	}
+
+	state := opts.State
+	baseline := state.fresh && !opts.EmitExisting
+
+	var eventErr error
+	for _, event := range state.Changes(repo, packages, opts.Clock.Now()) {
+		if !baseline && opts.OnEvent != nil {
+			if err := opts.OnEvent(event); err != nil {
+				eventErr = &WatchEventError{Event: event, Err: err}
+				break
+			}
+		}
+		state.Record(repo, event.Package)
+	}
+	if eventErr == nil {
+		state.Forget(repo, packages)
+	}
+	state.fresh = false
+
+	if opts.StatePath != "" {
+		if err := state.Save(opts.StatePath); err != nil {
+			return errors.Join(eventErr, err)
+		}
+	}
+
+	return eventErr
+}
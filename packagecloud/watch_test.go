@@ -0,0 +1,138 @@
+package packagecloud
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+func TestWatchReportsChanges(t *testing.T) {
+	first := types.PackageFragments{{Filename: "a_1.0.deb", DistroVersion: "ubuntu/jammy", Indexed: true}}
+	second := types.PackageFragments{
+		{Filename: "a_1.0.deb", DistroVersion: "ubuntu/jammy", Indexed: true},
+		{Filename: "a_1.1.deb", DistroVersion: "ubuntu/jammy"},
+	}
+	third := types.PackageFragments{
+		{Filename: "a_1.0.deb", DistroVersion: "ubuntu/jammy", Indexed: true},
+		{Filename: "a_1.1.deb", DistroVersion: "ubuntu/jammy", Indexed: true},
+	}
+	server := newFakeServer(t, nil)
+	server.sequence("user/repo", first, second, third)
+	client := server.client()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	var events []string
+	poll := func() {
+		t.Helper()
+		state, err := LoadWatchState(statePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Watch(context.Background(), SearchOptions{
+			RepoUser: "user",
+			RepoName: "repo",
+			Query:    "a",
+		}, WatchOptions{
+			State:     state,
+			StatePath: statePath,
+			Once:      true,
+			OnEvent: func(event WatchEvent) error {
+				events = append(events, string(event.Type)+" "+event.Package.Filename)
+				return nil
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the first poll of a fresh state only records the existing packages
+	poll()
+	poll()
+	poll()
+
+	expected := []string{"new a_1.1.deb", "indexed a_1.1.deb"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestWatchEmitExisting(t *testing.T) {
+	server := newFakeServer(t, map[string]types.PackageFragments{"user/repo": {{Filename: "a_1.0.deb"}}})
+	client := server.client()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var events []WatchEvent
+	err := client.Watch(ctx, SearchOptions{
+		RepoUser: "user",
+		RepoName: "repo",
+		Query:    "a",
+	}, WatchOptions{
+		Interval:     time.Minute,
+		EmitExisting: true,
+		Clock:        clock,
+		OnEvent: func(event WatchEvent) error {
+			events = append(events, event)
+			cancel()
+			return nil
+		},
+		OnError: func(err error) {
+			t.Error(err)
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the watch to be canceled, got %v", err)
+	}
+	if server.searches("user/repo") != 1 {
+		t.Errorf("expected 1 search request, got %d", server.searches("user/repo"))
+	}
+	if len(events) != 1 || events[0].Type != WatchEventNew || events[0].Repository != "user/repo" {
+		t.Errorf("expected a new package event, got %+v", events)
+	}
+	if !events[0].Time.Equal(time.Unix(0, 0)) {
+		t.Errorf("expected the event time to come from the clock, got %s", events[0].Time)
+	}
+}
+
+func TestWatchStopsOnEventError(t *testing.T) {
+	server := newFakeServer(t, map[string]types.PackageFragments{"user/repo": {{Filename: "a_1.0.deb"}, {Filename: "b_1.0.deb"}}})
+	client := server.client()
+	state := NewWatchState()
+	errStop := errors.New("stop")
+
+	err := client.Watch(context.Background(), SearchOptions{
+		RepoUser: "user",
+		RepoName: "repo",
+		Query:    "deb",
+	}, WatchOptions{
+		State:        state,
+		EmitExisting: true,
+		OnEvent: func(event WatchEvent) error {
+			if event.Package.Filename == "b_1.0.deb" {
+				return errStop
+			}
+			return nil
+		},
+		OnError: func(err error) {
+			t.Errorf("expected the event error to stop the watch, got %s", err)
+		},
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected the event error, got %v", err)
+	}
+	var eventErr *WatchEventError
+	if !errors.As(err, &eventErr) || eventErr.Event.Package.Filename != "b_1.0.deb" {
+		t.Errorf("expected a *WatchEventError for b_1.0.deb, got %v", err)
+	}
+
+	expected := map[string]bool{"user/repo//a_1.0.deb": false}
+	if !reflect.DeepEqual(state.Packages, expected) {
+		t.Errorf("expected only the reported package to be recorded, got %v", state.Packages)
+	}
+}
@@ -0,0 +1,161 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+var (
+	ErrGatesFailed = errors.New("one or more gates did not pass")
+)
+
+type AdvanceOptions struct {
+	// PackageName is the name of the package to advance.
+	PackageName string
+
+	// Version is the version of the package to advance. Packages match if
+	// either their version or their version-release equals Version.
+	Version string
+
+	// Approved satisfies the manual approval gate.
+	Approved bool
+
+	// Now is the time used to evaluate the soak gate. Defaults to the
+	// current time.
+	Now time.Time
+
+	// Promote is passed through to the promotion of the packages.
+	Promote packagecloud.PromoteOptions
+}
+
+type AdvanceResult struct {
+	// From is the stage the package was found in.
+	From Stage
+
+	// To is the stage the package is promoted to.
+	To Stage
+
+	// Packages are the packages of the given version in the From stage that
+	// are not in the To stage yet.
+	Packages types.PackageFragments
+
+	// Gates are the results of evaluating the gates of the To stage.
+	Gates []GateResult
+
+	// Promotions are the results of promoting the packages. Empty if any
+	// gate failed.
+	Promotions []packagecloud.PromoteResult
+}
+
+// Advance finds the earliest stage of the pipeline holding packages of the
+// given version that are not in the stage that follows it yet, evaluates the
+// gates of that following stage against the packages of the version in both
+// stages and, if every gate passes, promotes the missing packages into it.
+// Advancing again after a promotion stopped partway promotes the remaining
+// packages. If a gate fails, ErrGatesFailed is returned along with the gate
+// results.
+func (p Pipeline) Advance(client *packagecloud.Client, opts AdvanceOptions) (*AdvanceResult, error) {
+	if opts.PackageName == "" {
+		return nil, errors.New("package name cannot be empty")
+	}
+	if opts.Version == "" {
+		return nil, errors.New("version cannot be empty")
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	found := make([]types.PackageFragments, len(p.Stages))
+	for i, stage := range p.Stages {
+		packages, err := p.findPackages(client, stage, opts)
+		if err != nil {
+			return nil, err
+		}
+		found[i] = packages
+	}
+
+	// The current stage is the earliest one holding packages that are not
+	// in the next stage yet, so that a promotion that stopped partway is
+	// resumed rather than skipped.
+	current := -1
+	var pending types.PackageFragments
+	for i := 0; i < len(p.Stages)-1 && current == -1; i++ {
+		pending = missingPackages(found[i], found[i+1])
+		if len(pending) > 0 {
+			current = i
+		}
+	}
+
+	if current == -1 {
+		if len(found[len(found)-1]) > 0 {
+			return nil, fmt.Errorf("%s %s is already in the final stage: %s", opts.PackageName, opts.Version, p.Stages[len(p.Stages)-1].Name)
+		}
+		return nil, fmt.Errorf("%s %s was not found in any stage", opts.PackageName, opts.Version)
+	}
+
+	result := &AdvanceResult{
+		From:     p.Stages[current],
+		To:       p.Stages[current+1],
+		Packages: pending,
+	}
+
+	// Promoting moves packages out of the current stage, so the packages
+	// already promoted by a promotion that stopped partway are only found
+	// in the next stage.
+	packages := append(types.PackageFragments{}, found[current]...)
+	packages = append(packages, missingPackages(found[current+1], found[current])...)
+	result.Gates = result.To.Gates.Evaluate(packages, opts.Now, opts.Approved)
+	for _, gate := range result.Gates {
+		if !gate.Passed {
+			return result, ErrGatesFailed
+		}
+	}
+
+	promotions, err := client.PromotePackages(result.From.Repository(), result.To.Repository(), pending, opts.Promote)
+	result.Promotions = promotions
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (p Pipeline) findPackages(client *packagecloud.Client, stage Stage, opts AdvanceOptions) (types.PackageFragments, error) {
+	packages, err := client.ListVersionPackages(packagecloud.ListVersionsOptions{
+		Repo:        stage.Repository(),
+		PackageName: opts.PackageName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages in stage %s: %w", stage.Name, err)
+	}
+
+	var matches types.PackageFragments
+	for _, pkg := range packages {
+		if pkg.Version == opts.Version || fmt.Sprintf("%s-%s", pkg.Version, pkg.Release) == opts.Version {
+			matches = append(matches, pkg)
+		}
+	}
+
+	return matches, nil
+}
+
+// missingPackages returns the packages of from that are not in to, matched
+// by distro version and filename.
+func missingPackages(from types.PackageFragments, to types.PackageFragments) types.PackageFragments {
+	existing := make(map[string]bool, len(to))
+	for _, pkg := range to {
+		existing[pkg.DistroVersion+"/"+pkg.Filename] = true
+	}
+
+	var missing types.PackageFragments
+	for _, pkg := range from {
+		if !existing[pkg.DistroVersion+"/"+pkg.Filename] {
+			missing = append(missing, pkg)
+		}
+	}
+	return missing
+}
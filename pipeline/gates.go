@@ -0,0 +1,122 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+type GateResult struct {
+	// Gate is the name of the gate (indexed, min_soak, require or
+	// manual_approval).
+	Gate string `json:"gate"`
+
+	// Passed specifies whether or not the gate passed.
+	Passed bool `json:"passed"`
+
+	// Message describes why the gate passed or failed.
+	Message string `json:"message"`
+}
+
+// Evaluate checks every configured gate against the packages in the
+// previous stage. now is used to compute how long the packages have soaked
+// and approved specifies whether or not the promotion was manually approved.
+func (g Gates) Evaluate(packages types.PackageFragments, now time.Time, approved bool) []GateResult {
+	var results []GateResult
+
+	if g.Indexed {
+		results = append(results, evaluateIndexed(packages))
+	}
+
+	if g.MinSoak > 0 {
+		results = append(results, evaluateSoak(packages, now, g.MinSoak))
+	}
+
+	for _, coverage := range g.Require {
+		results = append(results, evaluateCoverage(packages, coverage))
+	}
+
+	if g.ManualApproval {
+		result := GateResult{
+			Gate:    "manual_approval",
+			Passed:  approved,
+			Message: "approved",
+		}
+		if !approved {
+			result.Message = "promotion requires manual approval"
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func evaluateIndexed(packages types.PackageFragments) GateResult {
+	var pending []string
+	for _, pkg := range packages {
+		if !pkg.Indexed {
+			pending = append(pending, pkg.Filename)
+		}
+	}
+
+	if len(pending) > 0 {
+		return GateResult{
+			Gate:    "indexed",
+			Message: fmt.Sprintf("not indexed: %s", strings.Join(pending, ", ")),
+		}
+	}
+	return GateResult{
+		Gate:    "indexed",
+		Passed:  true,
+		Message: "all packages have been indexed",
+	}
+}
+
+func evaluateSoak(packages types.PackageFragments, now time.Time, minSoak time.Duration) GateResult {
+	var newest time.Time
+	for _, pkg := range packages {
+		created, err := pkg.CreatedTime()
+		if err != nil {
+			return GateResult{
+				Gate:    "min_soak",
+				Message: fmt.Sprintf("invalid created_at for %s: %s", pkg.Filename, err),
+			}
+		}
+		if created.After(newest) {
+			newest = created
+		}
+	}
+
+	soaked := now.Sub(newest)
+	if soaked < minSoak {
+		return GateResult{
+			Gate:    "min_soak",
+			Message: fmt.Sprintf("soaked for %s, requires %s", soaked.Round(time.Minute), minSoak),
+		}
+	}
+	return GateResult{
+		Gate:    "min_soak",
+		Passed:  true,
+		Message: fmt.Sprintf("soaked for %s", soaked.Round(time.Minute)),
+	}
+}
+
+func evaluateCoverage(packages types.PackageFragments, coverage Coverage) GateResult {
+	for _, pkg := range packages {
+		distMatches := coverage.Dist == "" || pkg.DistroVersion == coverage.Dist
+		archMatches := coverage.Arch == "" || pkg.Architecture == coverage.Arch
+		if distMatches && archMatches {
+			return GateResult{
+				Gate:    "require",
+				Passed:  true,
+				Message: fmt.Sprintf("found %s", coverage),
+			}
+		}
+	}
+	return GateResult{
+		Gate:    "require",
+		Message: fmt.Sprintf("missing %s", coverage),
+	}
+}
@@ -0,0 +1,128 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Pipeline describes an ordered list of stages that packages are promoted
+// through, i.e. dev -> staging -> production.
+type Pipeline struct {
+	Stages []Stage `yaml:"stages"`
+}
+
+type Stage struct {
+	// Name is the name of the stage.
+	Name string `yaml:"name"`
+
+	// Repo is the repository (user/repo) that holds the packages of this
+	// stage.
+	Repo string `yaml:"repo"`
+
+	// Gates must all pass before a package can be promoted into this stage
+	// from the previous one.
+	Gates Gates `yaml:"gates"`
+}
+
+type Gates struct {
+	// Indexed requires every package to have been indexed in the previous
+	// stage.
+	Indexed bool `yaml:"indexed"`
+
+	// MinSoak is the minimum amount of time that must have passed since the
+	// newest package was uploaded to the previous stage (i.e. 24h).
+	MinSoak time.Duration `yaml:"min_soak"`
+
+	// Require lists the distro/arch combinations that the previous stage
+	// must have a package for.
+	Require []Coverage `yaml:"require"`
+
+	// ManualApproval requires the promotion to be explicitly approved.
+	ManualApproval bool `yaml:"manual_approval"`
+}
+
+// Coverage is a distro and/or architecture that a package must be available
+// for.
+type Coverage struct {
+	// Dist is the distro (i.e. ubuntu/jammy) that a package must exist for.
+	// If empty, any distro matches.
+	Dist string `yaml:"dist"`
+
+	// Arch is the architecture (i.e. amd64) that a package must exist for.
+	// If empty, any architecture matches.
+	Arch string `yaml:"arch"`
+}
+
+func (c Coverage) String() string {
+	switch {
+	case c.Dist == "":
+		return c.Arch
+	case c.Arch == "":
+		return c.Dist
+	}
+	return fmt.Sprintf("%s %s", c.Dist, c.Arch)
+}
+
+// Load reads and validates the pipeline file at the given path.
+func Load(path string) (*Pipeline, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline file: %w", err)
+	}
+	return Parse(bytes)
+}
+
+// Parse parses and validates a YAML pipeline definition.
+func Parse(bytes []byte) (*Pipeline, error) {
+	var p Pipeline
+	if err := yaml.UnmarshalStrict(bytes, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("pipeline validation failed: %w", err)
+	}
+	return &p, nil
+}
+
+func (p Pipeline) Validate() error {
+	if len(p.Stages) < 2 {
+		return errors.New("a pipeline must have at least 2 stages")
+	}
+
+	names := map[string]struct{}{}
+	for i, stage := range p.Stages {
+		if stage.Name == "" {
+			return fmt.Errorf("stage %d has no name", i+1)
+		}
+		if _, ok := names[stage.Name]; ok {
+			return fmt.Errorf("duplicate stage name: %s", stage.Name)
+		}
+		names[stage.Name] = struct{}{}
+
+		if _, err := packagecloud.NewRepoFromString(stage.Repo); err != nil {
+			return fmt.Errorf("stage %s has an invalid repo: %w", stage.Name, err)
+		}
+		if stage.Gates.MinSoak < 0 {
+			return fmt.Errorf("stage %s has a negative min_soak", stage.Name)
+		}
+		for _, coverage := range stage.Gates.Require {
+			if coverage.Dist == "" && coverage.Arch == "" {
+				return fmt.Errorf("stage %s has a requirement without a dist or arch", stage.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Repository returns the repository of the stage. The pipeline must have
+// been validated.
+func (s Stage) Repository() packagecloud.Repo {
+	repo, _ := packagecloud.NewRepoFromString(s.Repo)
+	return repo
+}
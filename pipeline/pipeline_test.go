@@ -0,0 +1,237 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amdprophet/packagecloud-go/packagecloud"
+	"github.com/amdprophet/packagecloud-go/types"
+)
+
+const testPipeline = `
+stages:
+  - name: staging
+    repo: ecorp/staging
+  - name: production
+    repo: ecorp/production
+    gates:
+      indexed: true
+      min_soak: 24h
+      require:
+        - dist: ubuntu/jammy
+          arch: amd64
+        - dist: el/9
+      manual_approval: true
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(testPipeline))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Stages) != 2 {
+		t.Fatalf("expected 2 stages, got %d", len(p.Stages))
+	}
+
+	gates := p.Stages[1].Gates
+	if !gates.Indexed || !gates.ManualApproval || gates.MinSoak != 24*time.Hour || len(gates.Require) != 2 {
+		t.Errorf("unexpected gates: %+v", gates)
+	}
+	if repo := p.Stages[1].Repository(); repo.User != "ecorp" || repo.Name != "production" {
+		t.Errorf("unexpected repository: %s", repo)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"single stage":   "stages:\n  - name: a\n    repo: u/a\n",
+		"invalid repo":   "stages:\n  - name: a\n    repo: u/a\n  - name: b\n    repo: b\n",
+		"duplicate name": "stages:\n  - name: a\n    repo: u/a\n  - name: a\n    repo: u/b\n",
+		"unknown field":  "stages:\n  - name: a\n    repo: u/a\n  - name: b\n    repo: u/b\n    gates:\n      soak: 1h\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestGatesEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPipeline))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gates := p.Stages[1].Gates
+
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	packages := types.PackageFragments{
+		{Filename: "a_amd64.deb", DistroVersion: "ubuntu/jammy", Architecture: "amd64", Indexed: true, CreatedAt: "2024-01-01T00:00:00.000Z"},
+		{Filename: "a.x86_64.rpm", DistroVersion: "el/9", Architecture: "x86_64", Indexed: false, CreatedAt: "2024-01-02T00:00:00.000Z"},
+	}
+
+	results := gates.Evaluate(packages, now, false)
+	expected := map[string][]bool{
+		"indexed":         {false},
+		"min_soak":        {false},
+		"require":         {true, true},
+		"manual_approval": {false},
+	}
+	got := map[string][]bool{}
+	for _, result := range results {
+		got[result.Gate] = append(got[result.Gate], result.Passed)
+	}
+	for gate, passed := range expected {
+		if len(got[gate]) != len(passed) {
+			t.Errorf("expected %d %s results, got %d", len(passed), gate, len(got[gate]))
+			continue
+		}
+		for i := range passed {
+			if got[gate][i] != passed[i] {
+				t.Errorf("expected %s gate passed=%t, got %t", gate, passed[i], got[gate][i])
+			}
+		}
+	}
+
+	packages[1].Indexed = true
+	for _, result := range gates.Evaluate(packages, now.Add(24*time.Hour), true) {
+		if !result.Passed {
+			t.Errorf("expected %s gate to pass: %s", result.Gate, result.Message)
+		}
+	}
+}
+
+// stagePackage returns an ubuntu/jammy package of agent 3.2.0 in repo.
+func stagePackage(repo, filename string) types.PackageFragment {
+	return types.PackageFragment{
+		Name:          "agent",
+		Type:          "deb",
+		Version:       "3.2.0",
+		Release:       "1",
+		DistroVersion: "ubuntu/jammy",
+		Filename:      filename,
+		PromoteURL:    "/api/v1/repos/" + repo + "/ubuntu/jammy/" + filename + "/promote.json",
+	}
+}
+
+// newAdvanceServer returns a fake packagecloud API serving the packages of
+// each repository, and records the path of every promotion in promoted.
+func newAdvanceServer(t *testing.T, repos map[string]types.PackageFragments, promoted *[]string) *httptest.Server {
+	mux := http.NewServeMux()
+	for repo, packages := range repos {
+		packages := packages
+		mux.HandleFunc("/api/v1/repos/"+repo+"/packages.json", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(packages)
+		})
+		mux.HandleFunc("/api/v1/repos/"+repo+"/search.json", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(types.PackageFragments{})
+		})
+		mux.HandleFunc("/api/v1/repos/"+repo+"/ubuntu/jammy/", func(w http.ResponseWriter, r *http.Request) {
+			*promoted = append(*promoted, r.URL.Path)
+			w.Write([]byte("{}"))
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAdvanceResumesPartialPromotion(t *testing.T) {
+	// The promotion into production stopped after the first package.
+	repos := map[string]types.PackageFragments{
+		"ecorp/dev": {
+			stagePackage("ecorp/dev", "agent_3.2.0-1_amd64.deb"),
+			stagePackage("ecorp/dev", "agent_3.2.0-1_arm64.deb"),
+		},
+		"ecorp/staging": {
+			stagePackage("ecorp/staging", "agent_3.2.0-1_amd64.deb"),
+			stagePackage("ecorp/staging", "agent_3.2.0-1_arm64.deb"),
+		},
+		"ecorp/production": {
+			stagePackage("ecorp/production", "agent_3.2.0-1_amd64.deb"),
+		},
+	}
+
+	var promoted []string
+	server := newAdvanceServer(t, repos, &promoted)
+
+	p, err := Parse([]byte("stages:\n  - name: dev\n    repo: ecorp/dev\n  - name: staging\n    repo: ecorp/staging\n  - name: production\n    repo: ecorp/production\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := packagecloud.NewClient(packagecloud.Config{ServiceURL: server.URL, Token: "token"})
+	result, err := p.Advance(client, AdvanceOptions{PackageName: "agent", Version: "3.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.From.Name != "staging" || result.To.Name != "production" {
+		t.Errorf("expected to advance from staging to production, got %s to %s", result.From.Name, result.To.Name)
+	}
+	expected := []string{"/api/v1/repos/ecorp/staging/ubuntu/jammy/agent_3.2.0-1_arm64.deb/promote.json"}
+	if !reflect.DeepEqual(promoted, expected) {
+		t.Errorf("expected promotions %v, got %v", expected, promoted)
+	}
+}
+
+func TestAdvanceGatesIncludePromotedPackages(t *testing.T) {
+	archPackage := func(repo, arch string) types.PackageFragment {
+		pkg := stagePackage(repo, "agent_3.2.0-1_"+arch+".deb")
+		pkg.Architecture = arch
+		return pkg
+	}
+
+	// Promoting moves packages, so the amd64 package promoted before the
+	// promotion stopped is only in production.
+	repos := map[string]types.PackageFragments{
+		"ecorp/staging": {
+			archPackage("ecorp/staging", "arm64"),
+		},
+		"ecorp/production": {
+			archPackage("ecorp/production", "amd64"),
+		},
+	}
+
+	var promoted []string
+	server := newAdvanceServer(t, repos, &promoted)
+
+	p, err := Parse([]byte(`
+stages:
+  - name: staging
+    repo: ecorp/staging
+  - name: production
+    repo: ecorp/production
+    gates:
+      require:
+        - dist: ubuntu/jammy
+          arch: amd64
+        - dist: ubuntu/jammy
+          arch: arm64
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := packagecloud.NewClient(packagecloud.Config{ServiceURL: server.URL, Token: "token"})
+	result, err := p.Advance(client, AdvanceOptions{PackageName: "agent", Version: "3.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, gate := range result.Gates {
+		if !gate.Passed {
+			t.Errorf("expected %s gate to pass: %s", gate.Gate, gate.Message)
+		}
+	}
+	expected := []string{"/api/v1/repos/ecorp/staging/ubuntu/jammy/agent_3.2.0-1_arm64.deb/promote.json"}
+	if !reflect.DeepEqual(promoted, expected) {
+		t.Errorf("expected promotions %v, got %v", expected, promoted)
+	}
+}
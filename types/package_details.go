@@ -1,5 +1,7 @@
 package types
 
+import "errors"
+
 type PackageDetails struct {
 	// Name is the name of the package.
 	Name string `json:"name"`
@@ -103,3 +105,38 @@ type PackageDetails struct {
 	// SelfURL is the API URL for this response.
 	SelfURL string `json:"self_url"`
 }
+
+// Checksum returns the strongest checksum available for the package along
+// with the name of its algorithm. Both are empty if no checksum is
+// available.
+func (p PackageDetails) Checksum() (string, string) {
+	switch {
+	case p.SHA512Sum != "":
+		return "sha512", p.SHA512Sum
+	case p.SHA256Sum != "":
+		return "sha256", p.SHA256Sum
+	case p.SHA1Sum != "":
+		return "sha1", p.SHA1Sum
+	case p.MD5Sum != "":
+		return "md5", p.MD5Sum
+	}
+	return "", ""
+}
+
+// SameChecksum compares the strongest checksum available for both packages
+// and returns true if they match. An error is returned if the packages have
+// no checksum algorithm in common.
+func (p PackageDetails) SameChecksum(other PackageDetails) (bool, error) {
+	pairs := [][2]string{
+		{p.SHA512Sum, other.SHA512Sum},
+		{p.SHA256Sum, other.SHA256Sum},
+		{p.SHA1Sum, other.SHA1Sum},
+		{p.MD5Sum, other.MD5Sum},
+	}
+	for _, pair := range pairs {
+		if pair[0] != "" && pair[1] != "" {
+			return pair[0] == pair[1], nil
+		}
+	}
+	return false, errors.New("packages have no checksum in common")
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type PackageFragments []PackageFragment
 
 func (p PackageFragments) Indexed() bool {
@@ -79,3 +81,8 @@ type PackageFragment struct {
 	// package.
 	DestroyURL string `json:"destroy_url"`
 }
+
+// CreatedTime parses CreatedAt as an RFC 3339 timestamp.
+func (p PackageFragment) CreatedTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, p.CreatedAt)
+}
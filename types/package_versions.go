@@ -1,60 +1,312 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
-
-	semver "github.com/Masterminds/semver/v3"
+	"strconv"
+	"strings"
+	"time"
 )
 
-type PackageVersions map[string]int
+// PackageVersion describes every package with the same epoch, version and
+// release.
+type PackageVersion struct {
+	// Epoch is the epoch of the packages (if available).
+	Epoch int `json:"epoch"`
+
+	// Version is the version of the packages.
+	Version string `json:"version"`
+
+	// Release is the release of the packages (if available).
+	Release string `json:"release"`
+
+	// Distros is the sorted list of distro versions the packages are in.
+	Distros []string `json:"distros"`
+
+	// Architectures is the sorted list of architectures of the packages.
+	Architectures []string `json:"architectures"`
+
+	// PackageCount is the number of packages with this version.
+	PackageCount int `json:"package_count"`
+
+	// LatestCreatedAt is when the most recent package with this version was
+	// uploaded.
+	LatestCreatedAt time.Time `json:"latest_created_at"`
+}
+
+// PackageVersionKey returns the key of the version of a package in the
+// format [epoch:]version[-release] used by dpkg and rpm. Other schemes have
+// no notion of a package release, so it is appended as build metadata
+// instead ([epoch:]version[+release]), which keeps the key unique without the
+// release being compared as a pre-release (i.e. 1.2.3+r0 for an alpine
+// package).
+func PackageVersionKey(scheme VersionScheme, epoch int, version string, release string) string {
+	key := version
+	if epoch > 0 {
+		key = strconv.Itoa(epoch) + ":" + key
+	}
+	switch {
+	case release == "":
+		return key
+	case scheme == DpkgScheme || scheme == RpmScheme:
+		return key + "-" + release
+	case strings.Contains(key, "+"):
+		return key + "." + release
+	default:
+		return key + "+" + release
+	}
+}
+
+// Key returns the key of the version for the given scheme. See
+// PackageVersionKey.
+func (v PackageVersion) Key(scheme VersionScheme) string {
+	return PackageVersionKey(scheme, v.Epoch, v.Version, v.Release)
+}
+
+// add records pkg as having this version.
+func (v *PackageVersion) add(pkg PackageFragment) {
+	v.PackageCount++
+	v.Distros = insertSorted(v.Distros, pkg.DistroVersion)
+	v.Architectures = insertSorted(v.Architectures, pkg.Architecture)
 
-func (p PackageVersions) SemanticVersions() ([]*semver.Version, error) {
-	versions := []*semver.Version{}
+	if createdAt, err := pkg.CreatedTime(); err == nil && createdAt.After(v.LatestCreatedAt) {
+		v.LatestCreatedAt = createdAt
+	}
+}
 
-	for version := range p {
-		v, err := semver.NewVersion(version)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing version: %s", err)
+// insertSorted inserts s into the sorted slice values unless it is empty or
+// already present.
+func insertSorted(values []string, s string) []string {
+	if s == "" {
+		return values
+	}
+	i := sort.SearchStrings(values, s)
+	if i < len(values) && values[i] == s {
+		return values
+	}
+	values = append(values, "")
+	copy(values[i+1:], values[i:])
+	values[i] = s
+	return values
+}
+
+// PackageVersions is every version of a package, keyed by
+// PackageVersionKey, along with the version scheme used to order
+// the versions.
+type PackageVersions struct {
+	// Scheme is the version scheme used to order the versions. When nil,
+	// each version is ordered with the scheme of the type of its packages
+	// (see VersionSchemeForPackageType).
+	Scheme VersionScheme
+
+	// Versions is the versions of the package by key.
+	Versions map[string]*PackageVersion
+
+	// schemes is the scheme of each version by key when Scheme is nil.
+	schemes map[string]VersionScheme
+}
+
+func NewPackageVersions(scheme VersionScheme) PackageVersions {
+	return PackageVersions{
+		Scheme:   scheme,
+		Versions: map[string]*PackageVersion{},
+		schemes:  map[string]VersionScheme{},
+	}
+}
+
+func (p PackageVersions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Versions)
+}
+
+// Add records the version of pkg.
+func (p PackageVersions) Add(pkg PackageFragment) {
+	scheme, key := p.key(pkg)
+
+	version, ok := p.Versions[key]
+	if !ok {
+		// A version shared by package types with different schemes (i.e.
+		// 1.2.3-1 for both debs and rpms) keeps the scheme it was first
+		// added with.
+		p.schemes[key] = scheme
+		version = &PackageVersion{
+			Epoch:         pkg.Epoch,
+			Version:       pkg.Version,
+			Release:       pkg.Release,
+			Distros:       []string{},
+			Architectures: []string{},
 		}
-		versions = append(versions, v)
+		p.Versions[key] = version
 	}
 
-	return versions, nil
+	version.add(pkg)
 }
 
-func (p PackageVersions) ReverseSorted() ([]*semver.Version, error) {
-	versions, err := p.SemanticVersions()
-	if err != nil {
-		return nil, err
+// key returns the scheme and key of the version of pkg.
+func (p PackageVersions) key(pkg PackageFragment) (VersionScheme, string) {
+	scheme := p.Scheme
+	if scheme == nil {
+		scheme = VersionSchemeForPackageType(pkg.Type)
+	}
+	return scheme, PackageVersionKey(scheme, pkg.Epoch, pkg.Version, pkg.Release)
+}
+
+// SchemeOf returns the version scheme of the version with the given key.
+func (p PackageVersions) SchemeOf(key string) VersionScheme {
+	if p.Scheme != nil {
+		return p.Scheme
+	}
+	if scheme, ok := p.schemes[key]; ok {
+		return scheme
+	}
+	return SemverScheme
+}
+
+// CompareVersionsLenient compares the versions with keys a and b like
+// CompareVersionsLenient with their scheme. Versions of different schemes
+// are compared with the natural ordering of SortVersionsFallback.
+func (p PackageVersions) CompareVersionsLenient(a, b string) int {
+	scheme := p.SchemeOf(a)
+	if scheme.Name() != p.SchemeOf(b).Name() {
+		return dpkgVerRevCmp(a, b)
 	}
-	if len(versions) == 0 {
+	return CompareVersionsLenient(scheme, a, b)
+}
+
+// InvalidVersionsError returns an *InvalidVersionsError for the given
+// version keys, naming the schemes they are not valid in.
+func (p PackageVersions) InvalidVersionsError(keys []string) *InvalidVersionsError {
+	var names []string
+	for _, key := range keys {
+		names = insertSorted(names, p.SchemeOf(key).Name())
+	}
+	return &InvalidVersionsError{
+		Scheme:   strings.Join(names, " or "),
+		Versions: keys,
+	}
+}
+
+// Sorted returns the version keys that are valid in their scheme in
+// descending order (see ReverseSorted), followed separately by the keys that
+// are not, which are sorted with SortVersionsFallback.
+func (p PackageVersions) Sorted() (valid []string, invalid []string) {
+	for version := range p.Versions {
+		if _, err := p.SchemeOf(version).Compare(version, version); err != nil {
+			invalid = append(invalid, version)
+		} else {
+			valid = append(valid, version)
+		}
+	}
+
+	p.sortValid(valid)
+	SortVersionsFallback(invalid)
+
+	return valid, invalid
+}
+
+// ReverseSorted returns the version keys in descending order. The versions
+// of each scheme are ordered with it, and the versions of different schemes,
+// which can't be compared with each other, are interleaved with the natural
+// ordering of SortVersionsFallback. An *InvalidVersionsError is returned if
+// any version is not valid in its scheme.
+func (p PackageVersions) ReverseSorted() ([]string, error) {
+	if len(p.Versions) == 0 {
 		return nil, errors.New("no versions available")
 	}
 
-	// Sort versions in descending order
-	sort.Sort(sort.Reverse(semver.Collection(versions)))
+	valid, invalid := p.Sorted()
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, p.InvalidVersionsError(invalid)
+	}
 
-	return versions, nil
+	return valid, nil
 }
 
-func (p PackageVersions) LatestVersion() (string, error) {
-	versions, err := p.ReverseSorted()
-	if err != nil {
-		return "", err
+// sortValid sorts keys that are valid in their scheme in descending order,
+// as described by ReverseSorted.
+func (p PackageVersions) sortValid(keys []string) {
+	groups := map[string][]string{}
+	var names []string
+	for _, key := range keys {
+		name := p.SchemeOf(key).Name()
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], key)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		group := groups[name]
+		sort.Strings(group)
+		// Every version in the group is known to be valid, so this cannot
+		// fail.
+		SortVersions(p.SchemeOf(group[0]), group)
 	}
 
-	// Return the latest version as a string
-	return versions[0].String(), nil
+	for i := range keys {
+		next := ""
+		for _, name := range names {
+			if len(groups[name]) > 0 && (next == "" || dpkgVerRevCmp(groups[name][0], groups[next][0]) > 0) {
+				next = name
+			}
+		}
+		keys[i] = groups[next][0]
+		groups[next] = groups[next][1:]
+	}
 }
 
-func (p PackageVersions) PreviousVersion() (string, error) {
+// Select returns the versions that satisfy constraint, which may be nil to
+// select every version. Pre-releases are only selected when
+// includePrereleases is true.
+func (p PackageVersions) Select(constraint *VersionConstraint, includePrereleases bool) (PackageVersions, error) {
+	selected := NewPackageVersions(p.Scheme)
+
+	for key, version := range p.Versions {
+		scheme := p.SchemeOf(key)
+		if !includePrereleases && scheme.IsPrerelease(key) {
+			continue
+		}
+		if constraint != nil {
+			ok, err := constraint.Check(scheme, key)
+			if err != nil {
+				return PackageVersions{}, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		selected.Versions[key] = version
+		selected.schemes[key] = scheme
+	}
+
+	return selected, nil
+}
+
+// NthVersion returns the nth newest version, where 0 is the latest version,
+// 1 is the previous version and so on.
+func (p PackageVersions) NthVersion(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("invalid version index %d: must not be negative", n)
+	}
+
 	versions, err := p.ReverseSorted()
 	if err != nil {
 		return "", err
 	}
+	if n >= len(versions) {
+		return "", fmt.Errorf("cannot go back %d versions, only %d available", n, len(versions))
+	}
+
+	return versions[n], nil
+}
 
-	// Return the second latest version as a string
-	return versions[1].String(), nil
+func (p PackageVersions) LatestVersion() (string, error) {
+	return p.NthVersion(0)
+}
+
+func (p PackageVersions) PreviousVersion() (string, error) {
+	return p.NthVersion(1)
 }
@@ -0,0 +1,130 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPackageVersionsAdd(t *testing.T) {
+	versions := NewPackageVersions(DpkgScheme)
+	versions.Add(PackageFragment{Version: "2.4.1", Release: "3ubuntu1", Epoch: 1, DistroVersion: "ubuntu/noble", Architecture: "amd64", CreatedAt: "2024-01-02T00:00:00Z"})
+	versions.Add(PackageFragment{Version: "2.4.1", Release: "3ubuntu1", Epoch: 1, DistroVersion: "ubuntu/jammy", Architecture: "arm64", CreatedAt: "2024-03-04T00:00:00Z"})
+	versions.Add(PackageFragment{Version: "2.4.1", Release: "3ubuntu1", Epoch: 1, DistroVersion: "ubuntu/jammy", Architecture: "amd64", CreatedAt: "2024-02-03T00:00:00Z"})
+	versions.Add(PackageFragment{Version: "2.4.1", Release: "3ubuntu2", DistroVersion: "ubuntu/jammy", Architecture: "amd64"})
+
+	if len(versions.Versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(versions.Versions))
+	}
+
+	got := versions.Versions["1:2.4.1-3ubuntu1"]
+	if got == nil {
+		t.Fatalf("expected version 1:2.4.1-3ubuntu1, got %v", versions.Versions)
+	}
+
+	want := &PackageVersion{
+		Epoch:           1,
+		Version:         "2.4.1",
+		Release:         "3ubuntu1",
+		Distros:         []string{"ubuntu/jammy", "ubuntu/noble"},
+		Architectures:   []string{"amd64", "arm64"},
+		PackageCount:    3,
+		LatestCreatedAt: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// The epoch bump wins over the newer revision.
+	latest, err := versions.LatestVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != "1:2.4.1-3ubuntu1" {
+		t.Errorf("expected latest version 1:2.4.1-3ubuntu1, got %s", latest)
+	}
+}
+
+func TestPackageVersionsReverseSorted(t *testing.T) {
+	versions := NewPackageVersions(DpkgScheme)
+	for _, pkg := range []PackageFragment{
+		{Version: "1.0"},
+		{Version: "0.9", Epoch: 1},
+		{Version: "1.0~rc1"},
+		{Version: "1.0", Release: "1"},
+		{Version: "2.0.0.201"},
+		{Version: "2.0.0.3"},
+	} {
+		versions.Add(pkg)
+	}
+
+	got, err := versions.ReverseSorted()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"1:0.9", "2.0.0.201", "2.0.0.3", "1.0-1", "1.0", "1.0~rc1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPackageVersionsSortedInvalid(t *testing.T) {
+	versions := NewPackageVersions(SemverScheme)
+	for _, version := range []string{"1.2.0", "9.snapshot", "20240101.git3f2a", "1.10.0", "20240102.git1"} {
+		versions.Add(PackageFragment{Version: version})
+	}
+
+	valid, invalid := versions.Sorted()
+	if want := []string{"1.10.0", "1.2.0"}; !reflect.DeepEqual(valid, want) {
+		t.Errorf("got valid versions %v, want %v", valid, want)
+	}
+	if want := []string{"20240102.git1", "20240101.git3f2a", "9.snapshot"}; !reflect.DeepEqual(invalid, want) {
+		t.Errorf("got invalid versions %v, want %v", invalid, want)
+	}
+
+	_, err := versions.ReverseSorted()
+	var invalidErr *InvalidVersionsError
+	if !errors.As(err, &invalidErr) || len(invalidErr.Versions) != 3 {
+		t.Errorf("expected an InvalidVersionsError listing 3 versions, got %v", err)
+	}
+}
+
+func TestPackageVersionsRelease(t *testing.T) {
+	// Alpine packages have a release but no native version scheme.
+	versions := NewPackageVersions(SemverScheme)
+	for _, pkg := range []PackageFragment{
+		{Version: "1.2.3", Release: "r0"},
+		{Version: "1.2.3", Release: "r1"},
+		{Version: "1.2.4", Release: "r0"},
+		{Version: "1.3.0-rc.1", Release: "r0"},
+	} {
+		versions.Add(pkg)
+	}
+
+	selected, err := versions.Select(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted, err := selected.ReverseSorted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"1.2.4+r0", "1.2.3+r1", "1.2.3+r0"}
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("expected %v, got %v", expected, sorted)
+	}
+
+	constraint, err := ParseVersionConstraint("<=1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected, err = versions.Select(constraint, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected.Versions) != 2 {
+		t.Errorf("expected 2 versions matching <=1.2.3, got %v", selected.Versions)
+	}
+}
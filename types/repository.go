@@ -0,0 +1,26 @@
+package types
+
+type Repository struct {
+	// Name is the name of the repository.
+	Name string `json:"name"`
+
+	// FQName is the fully qualified name of the repository (user/repo).
+	FQName string `json:"fqname"`
+
+	// CreatedAt is a timestamp of when the repository was created.
+	CreatedAt string `json:"created_at"`
+
+	// URL is the HTML URL of the repository.
+	URL string `json:"url"`
+
+	// LastPushHuman is a human readable description of when a package was
+	// last pushed to the repository (i.e. "about 2 hours ago").
+	LastPushHuman string `json:"last_push_human"`
+
+	// PackageCountHuman is a human readable number of packages in the
+	// repository (i.e. "12 packages").
+	PackageCountHuman string `json:"package_count_human"`
+
+	// Private specifies whether or not the repository is private.
+	Private bool `json:"private"`
+}
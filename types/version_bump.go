@@ -0,0 +1,205 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionBump is the part of a version to increment.
+type VersionBump string
+
+const (
+	BumpMajor      VersionBump = "major"
+	BumpMinor      VersionBump = "minor"
+	BumpPatch      VersionBump = "patch"
+	BumpPrerelease VersionBump = "prerelease"
+)
+
+// ParseVersionBump parses major, minor, patch or prerelease.
+func ParseVersionBump(s string) (VersionBump, error) {
+	switch bump := VersionBump(s); bump {
+	case BumpMajor, BumpMinor, BumpPatch, BumpPrerelease:
+		return bump, nil
+	}
+	return "", fmt.Errorf("invalid version bump %q (must be one of: major, minor, patch, prerelease)", s)
+}
+
+// DefaultPrereleaseID is the pre-release identifier used by BumpVersion when
+// none is given.
+const DefaultPrereleaseID = "rc"
+
+// prereleaseStyle describes how a version scheme writes pre-releases.
+type prereleaseStyle struct {
+	// separator comes between the release and the pre-release identifier.
+	separator string
+
+	// numberSeparator comes between the pre-release identifier and its
+	// number.
+	numberSeparator string
+
+	// first is the number of the first pre-release.
+	first int
+}
+
+var (
+	prereleaseStyles = map[string]prereleaseStyle{
+		"semver":   {separator: "-", numberSeparator: ".", first: 0},
+		"npm":      {separator: "-", numberSeparator: ".", first: 0},
+		"dpkg":     {separator: "~", first: 1},
+		"rpm":      {separator: "~", first: 1},
+		"pep440":   {first: 1},
+		"rubygems": {separator: ".", first: 1},
+	}
+
+	pep440PrereleaseIDs = map[string]string{
+		"a":     "a",
+		"alpha": "a",
+		"b":     "b",
+		"beta":  "b",
+		"c":     "rc",
+		"rc":    "rc",
+	}
+
+	bumpEpochPattern = regexp.MustCompile(`^(\d+[:!])?(.*)$`)
+
+	bumpReleasePattern = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(.*)$`)
+
+	bumpPrereleasePattern = regexp.MustCompile(`^[-~.]?([A-Za-z]+)[-.]?(\d+)?$`)
+)
+
+// BumpVersion returns the version after version when incrementing the given
+// part, written in the style of scheme. The epoch is kept and any package
+// release or build metadata is dropped, so version may be a key returned by
+// PackageVersionKey for any scheme (i.e. 1:2.4.1-3ubuntu1 or 1.2.3+r0).
+// Pre-releases use preID (i.e. rc) as their identifier, or
+// DefaultPrereleaseID if it is empty.
+//
+// Bumping a pre-release to a major, minor or patch release returns the
+// release it precedes when possible (i.e. 2.0.0-rc.1 becomes 2.0.0 for a
+// major bump), while bumping a release to a pre-release starts a
+// pre-release of the next patch version.
+func BumpVersion(scheme VersionScheme, version string, bump VersionBump, preID string) (string, error) {
+	style, ok := prereleaseStyles[scheme.Name()]
+	if !ok {
+		return "", fmt.Errorf("version scheme %s does not support bumping versions", scheme.Name())
+	}
+
+	if preID == "" {
+		preID = DefaultPrereleaseID
+	}
+	if scheme == Pep440Scheme {
+		normalized, ok := pep440PrereleaseIDs[strings.ToLower(preID)]
+		if !ok {
+			return "", fmt.Errorf("invalid pre-release identifier %q for pep440 (must be a, b or rc)", preID)
+		}
+		preID = normalized
+	}
+
+	match := bumpEpochPattern.FindStringSubmatch(strings.TrimSpace(version))
+	epoch, rest := match[1], match[2]
+
+	// Drop the package release, which PackageVersionKey appends after a "-"
+	// for dpkg and rpm and as build metadata for every other scheme, and any
+	// build metadata.
+	if scheme == DpkgScheme || scheme == RpmScheme {
+		if i := strings.LastIndex(rest, "-"); i != -1 {
+			rest = rest[:i]
+		}
+	}
+	if i := strings.Index(rest, "+"); i != -1 {
+		rest = rest[:i]
+	}
+
+	match = bumpReleasePattern.FindStringSubmatch(rest)
+	if match == nil {
+		return "", fmt.Errorf("cannot bump version %s: it does not start with a number", version)
+	}
+
+	var components []int
+	for _, part := range strings.Split(match[1], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return "", fmt.Errorf("cannot bump version %s: %w", version, err)
+		}
+		components = append(components, n)
+	}
+	for len(components) < 3 {
+		components = append(components, 0)
+	}
+
+	var currentID string
+	var currentNumber int
+	hasNumber := false
+	prerelease := scheme.IsPrerelease(version) && match[2] != ""
+	if prerelease {
+		pre := bumpPrereleasePattern.FindStringSubmatch(match[2])
+		if pre == nil {
+			return "", fmt.Errorf("cannot bump version %s: unsupported pre-release %s", version, match[2])
+		}
+		currentID = pre[1]
+		if pre[2] != "" {
+			currentNumber, _ = strconv.Atoi(pre[2])
+			hasNumber = true
+		}
+		if scheme == Pep440Scheme {
+			currentID = pep440PrereleaseIDs[strings.ToLower(currentID)]
+		}
+	}
+
+	major, minor, patch := components[0], components[1], components[2]
+	release := func(major, minor, patch int) string {
+		return fmt.Sprintf("%s%d.%d.%d", epoch, major, minor, patch)
+	}
+	withPrerelease := func(release string, id string, number int) string {
+		return fmt.Sprintf("%s%s%s%s%d", release, style.separator, id, style.numberSeparator, number)
+	}
+
+	var next string
+	switch bump {
+	case BumpMajor:
+		if prerelease && minor == 0 && patch == 0 {
+			next = release(major, 0, 0)
+		} else {
+			next = release(major+1, 0, 0)
+		}
+	case BumpMinor:
+		if prerelease && patch == 0 {
+			next = release(major, minor, 0)
+		} else {
+			next = release(major, minor+1, 0)
+		}
+	case BumpPatch:
+		if prerelease {
+			next = release(major, minor, patch)
+		} else {
+			next = release(major, minor, patch+1)
+		}
+	case BumpPrerelease:
+		// Keep the release of the current pre-release as it is so that only
+		// the pre-release changes.
+		current := epoch + match[1]
+
+		switch {
+		case prerelease && currentID == preID && hasNumber:
+			next = withPrerelease(current, preID, currentNumber+1)
+		case prerelease:
+			next = withPrerelease(current, preID, style.first)
+		default:
+			next = withPrerelease(release(major, minor, patch+1), preID, style.first)
+		}
+	default:
+		return "", fmt.Errorf("invalid version bump %q", bump)
+	}
+
+	cmp, err := scheme.Compare(next, version)
+	if err != nil {
+		return "", fmt.Errorf("cannot bump version %s: %w", version, err)
+	}
+	if cmp <= 0 {
+		return "", fmt.Errorf("cannot bump version %s: %s would not be newer", version, next)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		scheme  VersionScheme
+		version string
+		bump    VersionBump
+		preID   string
+		want    string
+	}{
+		{SemverScheme, "1.4.2", BumpMajor, "", "2.0.0"},
+		{SemverScheme, "1.4.2", BumpMinor, "", "1.5.0"},
+		{SemverScheme, "1.4.2", BumpPatch, "", "1.4.3"},
+		{SemverScheme, "1.4.2", BumpPrerelease, "", "1.4.3-rc.0"},
+		{SemverScheme, "1.4.2", BumpPrerelease, "beta", "1.4.3-beta.0"},
+		{SemverScheme, "1.4.3-rc.0", BumpPrerelease, "", "1.4.3-rc.1"},
+		{SemverScheme, "1.4.3-beta.2", BumpPrerelease, "rc", "1.4.3-rc.0"},
+		{SemverScheme, "1.4.3-rc.1", BumpPatch, "", "1.4.3"},
+		{SemverScheme, "1.5.0-rc.1", BumpMinor, "", "1.5.0"},
+		{SemverScheme, "2.0.0-rc.1", BumpMajor, "", "2.0.0"},
+		{SemverScheme, "1.4.3-rc.1", BumpMajor, "", "2.0.0"},
+		{SemverScheme, "v1.2", BumpPatch, "", "1.2.1"},
+		{SemverScheme, "1.2.3+build.5", BumpPatch, "", "1.2.4"},
+		{SemverScheme, PackageVersionKey(SemverScheme, 0, "1.2.3", "r0"), BumpPatch, "", "1.2.4"},
+		{SemverScheme, PackageVersionKey(SemverScheme, 0, "1.2.3", "r0"), BumpPrerelease, "", "1.2.4-rc.0"},
+		{SemverScheme, PackageVersionKey(SemverScheme, 0, "1.3.0-rc.1", "r2"), BumpPrerelease, "", "1.3.0-rc.2"},
+		{NpmScheme, PackageVersionKey(NpmScheme, 0, "1.2.3", "r0"), BumpMinor, "", "1.3.0"},
+		{NpmScheme, "1.2.3", BumpPrerelease, "alpha", "1.2.4-alpha.0"},
+		{DpkgScheme, "1:2.4.1-3ubuntu1", BumpMinor, "", "1:2.5.0"},
+		{DpkgScheme, "2.4.1-1", BumpPrerelease, "", "2.4.2~rc1"},
+		{DpkgScheme, "2.5~rc1-1", BumpPrerelease, "", "2.5~rc2"},
+		{DpkgScheme, "2.5~rc1-1", BumpMinor, "", "2.5.0"},
+		{RpmScheme, "3.2.0-1.el9", BumpPatch, "", "3.2.1"},
+		{RpmScheme, "3.2.1~rc2-1.el9", BumpPatch, "", "3.2.1"},
+		{Pep440Scheme, "1.0.0", BumpPrerelease, "", "1.0.1rc1"},
+		{Pep440Scheme, "1.0.1rc1", BumpPrerelease, "", "1.0.1rc2"},
+		{Pep440Scheme, "1.0.1a1", BumpPrerelease, "beta", "1.0.1b1"},
+		{Pep440Scheme, "1.0.1.dev3", BumpPrerelease, "", "1.0.1rc1"},
+		{Pep440Scheme, "1.0.1.dev3", BumpPatch, "", "1.0.1"},
+		{Pep440Scheme, "1!1.0.0", BumpMajor, "", "1!2.0.0"},
+		{RubygemsScheme, "1.0.0", BumpPrerelease, "", "1.0.1.rc1"},
+		{RubygemsScheme, "1.0.1.rc1", BumpPrerelease, "", "1.0.1.rc2"},
+		{RubygemsScheme, "1.0.1.pre", BumpPatch, "", "1.0.1"},
+	}
+
+	for _, tt := range tests {
+		got, err := BumpVersion(tt.scheme, tt.version, tt.bump, tt.preID)
+		if err != nil {
+			t.Errorf("%s: BumpVersion(%q, %s, %q) returned error: %s", tt.scheme.Name(), tt.version, tt.bump, tt.preID, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: BumpVersion(%q, %s, %q) = %q, want %q", tt.scheme.Name(), tt.version, tt.bump, tt.preID, got, tt.want)
+		}
+	}
+}
+
+func TestBumpVersionInvalid(t *testing.T) {
+	tests := []struct {
+		scheme  VersionScheme
+		version string
+		bump    VersionBump
+		preID   string
+	}{
+		{SemverScheme, "junk", BumpPatch, ""},
+		{SemverScheme, "1.0.0-rc.1", BumpPrerelease, "beta"},
+		{Pep440Scheme, "1.0.0", BumpPrerelease, "gamma"},
+		{SemverScheme, "1.0.0", VersionBump("huge"), ""},
+	}
+
+	for _, tt := range tests {
+		if got, err := BumpVersion(tt.scheme, tt.version, tt.bump, tt.preID); err == nil {
+			t.Errorf("%s: expected BumpVersion(%q, %s, %q) to fail, got %q", tt.scheme.Name(), tt.version, tt.bump, tt.preID, got)
+		}
+	}
+}
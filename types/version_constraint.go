@@ -0,0 +1,354 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionConstraint is a set of version ranges that can be checked against
+// versions of any VersionScheme. Comparators within a range are separated by
+// spaces or commas and must all match, while ranges are separated by "||"
+// and only one of them needs to match. The supported comparators are:
+//
+//	=1.4, ==1.4   equal to 1.4
+//	!=1.4         not equal to 1.4
+//	>1.4, >=1.4   newer than (or equal to) 1.4
+//	<1.4, <=1.4   older than (or equal to) 1.4
+//	~1.4.3        >=1.4.3 <1.5 (~1.4 is >=1.4 <1.5 and ~1 is >=1 <2)
+//	^1.4.3        >=1.4.3 <2 (^0.4.3 is >=0.4.3 <0.5)
+//	~>1.4, ~=1.4  >=1.4 <2 (RubyGems and PEP 440 compatible releases)
+//	1.4.x, 1.4.*  >=1.4 <1.5 (* or x on its own matches every version)
+//	1.2 - 1.4     >=1.2 <=1.4
+type VersionConstraint struct {
+	source string
+	ranges [][]versionComparator
+}
+
+type versionComparator struct {
+	op      string
+	version string
+}
+
+var (
+	versionOperators = []string{"~>", "~=", "!=", ">=", "<=", "==", "=", ">", "<", "~", "^"}
+
+	hyphenRangePattern = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+
+	versionPrefixPattern = regexp.MustCompile(`^(\d+[:!])?(\d+(?:\.\d+)*)`)
+)
+
+// ParseVersionConstraint parses a version constraint (i.e. "~1.4",
+// ">=2.0 <3.0 || 4.x").
+func ParseVersionConstraint(constraint string) (*VersionConstraint, error) {
+	c := &VersionConstraint{source: constraint}
+
+	for _, r := range strings.Split(constraint, "||") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			return nil, fmt.Errorf("invalid version constraint %q: empty range", constraint)
+		}
+
+		comparators, err := parseVersionRange(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+		c.ranges = append(c.ranges, comparators)
+	}
+
+	return c, nil
+}
+
+func (c *VersionConstraint) String() string {
+	return c.source
+}
+
+// Check returns whether or not version satisfies the constraint when
+// compared using the given scheme. An error is returned if version or any
+// version in the constraint is not valid in the scheme.
+func (c *VersionConstraint) Check(scheme VersionScheme, version string) (bool, error) {
+	for _, comparators := range c.ranges {
+		matches := true
+		for _, comparator := range comparators {
+			ok, err := comparator.check(scheme, version)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c versionComparator) check(scheme VersionScheme, version string) (bool, error) {
+	if c.op == "*" {
+		return true, nil
+	}
+
+	if scheme == DpkgScheme {
+		version = dpkgConstraintVersion(version, c.version)
+	}
+
+	cmp, err := scheme.Compare(version, c.version)
+	if err != nil {
+		return false, err
+	}
+
+	switch c.op {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	default:
+		return false, fmt.Errorf("unknown operator: %s", c.op)
+	}
+}
+
+// dpkgConstraintVersion drops the Debian revision of version when the
+// constraint version has none, so that like with rpm, 3.2.0 matches 3.2.0-1
+// and <=3.2.0 includes it. Invalid versions are returned unchanged.
+func dpkgConstraintVersion(version string, constraint string) string {
+	_, _, constraintRevision, err := parseDpkgVersion(constraint)
+	if err != nil || constraintRevision != "" {
+		return version
+	}
+
+	epoch, upstream, _, err := parseDpkgVersion(version)
+	if err != nil {
+		return version
+	}
+	if epoch != "" {
+		return epoch + ":" + upstream
+	}
+	return upstream
+}
+
+func parseVersionRange(r string) ([]versionComparator, error) {
+	if match := hyphenRangePattern.FindStringSubmatch(r); match != nil {
+		return []versionComparator{
+			{op: ">=", version: match[1]},
+			{op: "<=", version: match[2]},
+		}, nil
+	}
+
+	// Operators may be separated from their version by whitespace (i.e.
+	// ">= 1.4"), so join operator-only fields with the field after them.
+	var terms []string
+	var pending string
+	for _, field := range strings.FieldsFunc(r, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	}) {
+		if isVersionOperator(field) {
+			pending += field
+			continue
+		}
+		terms = append(terms, pending+field)
+		pending = ""
+	}
+	if pending != "" {
+		return nil, fmt.Errorf("operator %s is missing a version", pending)
+	}
+
+	var comparators []versionComparator
+	for _, term := range terms {
+		parsed, err := parseVersionTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, parsed...)
+	}
+
+	return comparators, nil
+}
+
+func isVersionOperator(s string) bool {
+	for _, op := range versionOperators {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseVersionTerm(term string) ([]versionComparator, error) {
+	var op string
+	for _, candidate := range versionOperators {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+	version := strings.TrimSpace(strings.TrimPrefix(term, op))
+	if version == "" {
+		return nil, fmt.Errorf("operator %s is missing a version", op)
+	}
+	if op == "==" {
+		op = "="
+	}
+
+	if isWildcardVersion(version) {
+		if op != "" && op != "=" {
+			return nil, fmt.Errorf("wildcard version %s cannot be used with operator %s", version, op)
+		}
+		return wildcardRange(version)
+	}
+
+	switch op {
+	case "", "=":
+		return []versionComparator{{op: "=", version: version}}, nil
+	case "~":
+		return tildeRange(version)
+	case "^":
+		return caretRange(version)
+	case "~>", "~=":
+		return compatibleRange(op, version)
+	default:
+		return []versionComparator{{op: op, version: version}}, nil
+	}
+}
+
+func isWildcardVersion(version string) bool {
+	for _, part := range strings.Split(version, ".") {
+		if part == "*" || part == "x" || part == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitVersionPrefix returns the optional epoch (i.e. "1:") and the leading
+// numeric components of version.
+func splitVersionPrefix(version string) (string, []int, error) {
+	match := versionPrefixPattern.FindStringSubmatch(version)
+	if match == nil {
+		return "", nil, fmt.Errorf("version %s does not start with a number", version)
+	}
+
+	var components []int
+	for _, part := range strings.Split(match[2], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid version %s: %w", version, err)
+		}
+		components = append(components, n)
+	}
+
+	return match[1], components, nil
+}
+
+func joinVersion(epoch string, components []int) string {
+	parts := make([]string, len(components))
+	for i, n := range components {
+		parts[i] = strconv.Itoa(n)
+	}
+	return epoch + strings.Join(parts, ".")
+}
+
+// bumpVersion returns the version with the component at index i incremented
+// and every component after it dropped.
+func bumpVersion(epoch string, components []int, i int) string {
+	bumped := append([]int{}, components[:i+1]...)
+	bumped[i]++
+	return joinVersion(epoch, bumped)
+}
+
+func wildcardRange(version string) ([]versionComparator, error) {
+	var fixed []string
+	for _, part := range strings.Split(version, ".") {
+		if part == "*" || part == "x" || part == "X" {
+			break
+		}
+		fixed = append(fixed, part)
+	}
+	if len(fixed) == 0 {
+		return []versionComparator{{op: "*"}}, nil
+	}
+
+	epoch, components, err := splitVersionPrefix(strings.Join(fixed, "."))
+	if err != nil {
+		return nil, err
+	}
+	if len(components) != len(fixed) {
+		return nil, fmt.Errorf("invalid wildcard version %s", version)
+	}
+
+	return []versionComparator{
+		{op: ">=", version: joinVersion(epoch, components)},
+		{op: "<", version: bumpVersion(epoch, components, len(components)-1)},
+	}, nil
+}
+
+func tildeRange(version string) ([]versionComparator, error) {
+	epoch, components, err := splitVersionPrefix(version)
+	if err != nil {
+		return nil, err
+	}
+
+	i := 0
+	if len(components) > 1 {
+		i = 1
+	}
+
+	return []versionComparator{
+		{op: ">=", version: version},
+		{op: "<", version: bumpVersion(epoch, components, i)},
+	}, nil
+}
+
+func caretRange(version string) ([]versionComparator, error) {
+	epoch, components, err := splitVersionPrefix(version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bump the first non-zero component, or the last one if they are all
+	// zero.
+	i := len(components) - 1
+	for j, n := range components {
+		if n != 0 {
+			i = j
+			break
+		}
+	}
+
+	return []versionComparator{
+		{op: ">=", version: version},
+		{op: "<", version: bumpVersion(epoch, components, i)},
+	}, nil
+}
+
+func compatibleRange(op string, version string) ([]versionComparator, error) {
+	epoch, components, err := splitVersionPrefix(version)
+	if err != nil {
+		return nil, err
+	}
+
+	i := len(components) - 2
+	if i < 0 {
+		if op == "~=" {
+			return nil, errors.New("~= requires a version with at least two components")
+		}
+		i = 0
+	}
+
+	return []versionComparator{
+		{op: ">=", version: version},
+		{op: "<", version: bumpVersion(epoch, components, i)},
+	}, nil
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	tests := []struct {
+		scheme     VersionScheme
+		constraint string
+		version    string
+		want       bool
+	}{
+		{SemverScheme, "1.4.2", "1.4.2", true},
+		{SemverScheme, "=1.4", "1.4.0", true},
+		{SemverScheme, "!=1.4.2", "1.4.2", false},
+		{SemverScheme, "<2.0.0", "1.9.9", true},
+		{SemverScheme, "<2.0.0", "2.0.0", false},
+		{SemverScheme, ">= 1.2, < 1.4", "1.3.5", true},
+		{SemverScheme, ">=1.2 <1.4", "1.4.0", false},
+		{SemverScheme, "~1.4", "1.4.9", true},
+		{SemverScheme, "~1.4", "1.5.0", false},
+		{SemverScheme, "~1.4.3", "1.4.2", false},
+		{SemverScheme, "~1", "1.9.0", true},
+		{SemverScheme, "^1.4.3", "1.9.0", true},
+		{SemverScheme, "^1.4.3", "2.0.0", false},
+		{SemverScheme, "^0.4.3", "0.5.0", false},
+		{SemverScheme, "^0.0.3", "0.0.4", false},
+		{SemverScheme, "1.x", "1.9.9", true},
+		{SemverScheme, "1.4.*", "1.5.0", false},
+		{SemverScheme, "*", "42.0.0", true},
+		{SemverScheme, "1.2 - 1.4", "1.4.0", true},
+		{SemverScheme, "1.2 - 1.4", "1.4.1", false},
+		{SemverScheme, "<1.0 || >=2.0", "1.5.0", false},
+		{SemverScheme, "<1.0 || >=2.0", "2.1.0", true},
+		{DpkgScheme, "~1.4", "1.4.2-3ubuntu1", true},
+		{DpkgScheme, ">=1:2.0", "2.4.1-1", false},
+		{DpkgScheme, "1:2.x", "1:2.4.1-1", true},
+		{DpkgScheme, "3.2.0", "3.2.0-1", true},
+		{DpkgScheme, "1:3.2.0", "1:3.2.0-1ubuntu2", true},
+		{DpkgScheme, "<=3.2.0", "3.2.0-1", true},
+		{DpkgScheme, "<3.2.0", "3.2.0-1", false},
+		{DpkgScheme, "!=3.2.0", "3.2.0-1", false},
+		{DpkgScheme, "3.2.0-1", "3.2.0-2", false},
+		{DpkgScheme, "<=3.2.0-1", "3.2.0-2", false},
+		{DpkgScheme, "3.2.0", "3.2.0~rc1-1", false},
+		{RpmScheme, "<2.0", "1.9^git1-1.el9", true},
+		{Pep440Scheme, "~=1.4.2", "1.4.9.post1", true},
+		{Pep440Scheme, "~=1.4.2", "1.5.0", false},
+		{RubygemsScheme, "~> 1.4", "1.9", true},
+		{RubygemsScheme, "~> 1.4", "2.0", false},
+	}
+
+	for _, tt := range tests {
+		constraint, err := ParseVersionConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseVersionConstraint(%q) returned error: %s", tt.constraint, err)
+			continue
+		}
+
+		got, err := constraint.Check(tt.scheme, tt.version)
+		if err != nil {
+			t.Errorf("%s: Check(%q, %q) returned error: %s", tt.scheme.Name(), tt.constraint, tt.version, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Check(%q, %q) = %t, want %t", tt.scheme.Name(), tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestVersionConstraintInvalid(t *testing.T) {
+	for _, constraint := range []string{"", ">=", "1.0 ||", ">1.x", "~abc", "~=1", "^"} {
+		if _, err := ParseVersionConstraint(constraint); err == nil {
+			t.Errorf("expected an error for %q", constraint)
+		}
+	}
+}
+
+func TestPackageVersionsSelect(t *testing.T) {
+	versions := NewPackageVersions(SemverScheme)
+	for _, version := range []string{"1.3.0", "1.4.0", "1.4.1", "1.5.0-rc.1", "2.0.0"} {
+		versions.Add(PackageFragment{Version: version})
+	}
+
+	constraint, err := ParseVersionConstraint("<2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selected, err := versions.Select(constraint, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest, _ := selected.LatestVersion(); latest != "1.4.1" {
+		t.Errorf("expected latest version 1.4.1, got %s", latest)
+	}
+	if nth, _ := selected.NthVersion(2); nth != "1.3.0" {
+		t.Errorf("expected version 1.3.0 two releases back, got %s", nth)
+	}
+	if _, err := selected.NthVersion(3); err == nil {
+		t.Error("expected an error when going back further than the number of versions")
+	}
+
+	selected, err = versions.Select(constraint, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest, _ := selected.LatestVersion(); latest != "1.5.0-rc.1" {
+		t.Errorf("expected latest version 1.5.0-rc.1, got %s", latest)
+	}
+}
+
+func TestPackageVersionsPreviousVersionSingle(t *testing.T) {
+	versions := NewPackageVersions(SemverScheme)
+	versions.Add(PackageFragment{Version: "1.0.0"})
+
+	if _, err := versions.PreviousVersion(); err == nil {
+		t.Error("expected an error when only one version exists")
+	}
+}
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+type dpkgScheme struct{}
+
+// DpkgScheme compares versions the way dpkg does: [epoch:]upstream[-revision]
+// where "~" sorts before everything, including the end of the version.
+var DpkgScheme VersionScheme = dpkgScheme{}
+
+func (dpkgScheme) Name() string {
+	return "dpkg"
+}
+
+func (dpkgScheme) Compare(a, b string) (int, error) {
+	epochA, upstreamA, revisionA, err := parseDpkgVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	epochB, upstreamB, revisionB, err := parseDpkgVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if c := compareNumeric(epochA, epochB); c != 0 {
+		return c, nil
+	}
+	if c := dpkgVerRevCmp(upstreamA, upstreamB); c != 0 {
+		return c, nil
+	}
+	return dpkgVerRevCmp(revisionA, revisionB), nil
+}
+
+// IsPrerelease returns whether or not the upstream version contains "~",
+// which is the Debian convention for versions that sort before a release.
+func (dpkgScheme) IsPrerelease(version string) bool {
+	_, upstream, _, err := parseDpkgVersion(version)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(upstream, "~")
+}
+
+// parseDpkgVersion splits a Debian version into its epoch, upstream version
+// and Debian revision.
+func parseDpkgVersion(version string) (epoch, upstream, revision string, err error) {
+	upstream = strings.TrimSpace(version)
+
+	if i := strings.Index(upstream, ":"); i != -1 {
+		epoch = upstream[:i]
+		upstream = upstream[i+1:]
+		if epoch == "" || !isDigits(epoch) {
+			return "", "", "", fmt.Errorf("error parsing version %s: epoch is not a number", version)
+		}
+	}
+
+	if i := strings.LastIndex(upstream, "-"); i != -1 {
+		revision = upstream[i+1:]
+		upstream = upstream[:i]
+		if revision == "" {
+			return "", "", "", fmt.Errorf("error parsing version %s: revision is empty", version)
+		}
+	}
+
+	if upstream == "" {
+		return "", "", "", fmt.Errorf("error parsing version %s: version is empty", version)
+	}
+	if !isDigit(upstream[0]) {
+		return "", "", "", fmt.Errorf("error parsing version %s: version does not start with a digit", version)
+	}
+
+	return epoch, upstream, revision, nil
+}
+
+// dpkgOrder returns the sort weight of a character in the non-digit part of
+// a version. The end of the string sorts after "~" and before everything
+// else, and letters sort before non-letters.
+func dpkgOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+// dpkgVerRevCmp is a port of verrevcmp from dpkg.
+func dpkgVerRevCmp(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			if c := dpkgOrder(a, i) - dpkgOrder(b, j); c != 0 {
+				return sign(c)
+			}
+			i++
+			j++
+		}
+
+		startA := i
+		for i < len(a) && isDigit(a[i]) {
+			i++
+		}
+		startB := j
+		for j < len(b) && isDigit(b[j]) {
+			j++
+		}
+
+		if c := compareNumeric(a[startA:i], b[startB:j]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
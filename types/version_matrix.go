@@ -0,0 +1,153 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Platform is a distro version and architecture that packages are built
+// for.
+type Platform struct {
+	// Distro is the distro version (i.e. ubuntu/jammy). When used as a
+	// requirement, an empty distro matches any distro.
+	Distro string `json:"distro"`
+
+	// Arch is the architecture (i.e. amd64). When used as a requirement, an
+	// empty architecture matches any architecture.
+	Arch string `json:"arch"`
+}
+
+// ParsePlatform parses a platform in the format distro[:arch] (i.e.
+// ubuntu/jammy:amd64, el/9 or :arm64).
+func ParsePlatform(s string) (Platform, error) {
+	distro, arch, _ := strings.Cut(strings.TrimSpace(s), ":")
+	if distro == "" && arch == "" {
+		return Platform{}, fmt.Errorf("invalid platform %q: expected distro[:arch]", s)
+	}
+	return Platform{Distro: distro, Arch: arch}, nil
+}
+
+func (p Platform) String() string {
+	switch {
+	case p.Distro == "":
+		return ":" + p.Arch
+	case p.Arch == "":
+		return p.Distro
+	}
+	return p.Distro + ":" + p.Arch
+}
+
+// Matches returns whether or not other satisfies p when p is used as a
+// requirement.
+func (p Platform) Matches(other Platform) bool {
+	return (p.Distro == "" || p.Distro == other.Distro) &&
+		(p.Arch == "" || p.Arch == other.Arch)
+}
+
+// VersionMatrix is the number of packages with each version for each
+// platform.
+type VersionMatrix struct {
+	// Versions is every version in the matrix, newest first.
+	Versions []string
+
+	// Platforms is every platform in the matrix, sorted by distro and
+	// architecture.
+	Platforms []Platform
+
+	counts map[string]map[Platform]int
+}
+
+// NewVersionMatrix builds a version matrix from packages, ordering versions
+// with the given scheme or, when it is nil, the scheme of each package's type
+// (see PackageVersions.ReverseSorted).
+func NewVersionMatrix(scheme VersionScheme, packages PackageFragments) (*VersionMatrix, error) {
+	m := &VersionMatrix{
+		counts: map[string]map[Platform]int{},
+	}
+
+	versions := NewPackageVersions(scheme)
+	platforms := map[Platform]bool{}
+	for _, pkg := range packages {
+		versions.Add(pkg)
+		_, version := versions.key(pkg)
+		platform := Platform{Distro: pkg.DistroVersion, Arch: pkg.Architecture}
+
+		if _, ok := m.counts[version]; !ok {
+			m.counts[version] = map[Platform]int{}
+		}
+		m.counts[version][platform]++
+
+		if !platforms[platform] {
+			platforms[platform] = true
+			m.Platforms = append(m.Platforms, platform)
+		}
+	}
+
+	if len(versions.Versions) > 0 {
+		sorted, err := versions.ReverseSorted()
+		if err != nil {
+			return nil, err
+		}
+		m.Versions = sorted
+	}
+	sortPlatforms(m.Platforms)
+
+	return m, nil
+}
+
+func sortPlatforms(platforms []Platform) {
+	sort.Slice(platforms, func(i, j int) bool {
+		if platforms[i].Distro != platforms[j].Distro {
+			return platforms[i].Distro < platforms[j].Distro
+		}
+		return platforms[i].Arch < platforms[j].Arch
+	})
+}
+
+// Count returns the number of packages with the given version for the given
+// platform.
+func (m *VersionMatrix) Count(version string, platform Platform) int {
+	return m.counts[version][platform]
+}
+
+// Missing returns the platforms in required that have no packages with the
+// given version.
+func (m *VersionMatrix) Missing(version string, required []Platform) []Platform {
+	var missing []Platform
+	for _, requirement := range required {
+		found := false
+		for platform, count := range m.counts[version] {
+			if count > 0 && requirement.Matches(platform) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, requirement)
+		}
+	}
+	return missing
+}
+
+// Require adds every fully specified platform in required to the matrix so
+// that missing cells are shown even when no version exists for them.
+func (m *VersionMatrix) Require(required []Platform) {
+	for _, requirement := range required {
+		if requirement.Distro == "" || requirement.Arch == "" {
+			continue
+		}
+
+		found := false
+		for _, platform := range m.Platforms {
+			if platform == requirement {
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.Platforms = append(m.Platforms, requirement)
+		}
+	}
+	sortPlatforms(m.Platforms)
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionMatrix(t *testing.T) {
+	packages := PackageFragments{
+		{Version: "3.2.0", Release: "1", DistroVersion: "ubuntu/jammy", Architecture: "amd64"},
+		{Version: "3.2.0", Release: "1", DistroVersion: "ubuntu/jammy", Architecture: "arm64"},
+		{Version: "3.2.0", Release: "1", DistroVersion: "el/9", Architecture: "x86_64"},
+		{Version: "3.1.0", Release: "1", DistroVersion: "ubuntu/jammy", Architecture: "amd64"},
+	}
+
+	matrix, err := NewVersionMatrix(DpkgScheme, packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"3.2.0-1", "3.1.0-1"}; !reflect.DeepEqual(matrix.Versions, want) {
+		t.Errorf("got versions %v, want %v", matrix.Versions, want)
+	}
+
+	jammyArm := Platform{Distro: "ubuntu/jammy", Arch: "arm64"}
+	if got := matrix.Count("3.2.0-1", jammyArm); got != 1 {
+		t.Errorf("expected 1 package for 3.2.0-1 on %s, got %d", jammyArm, got)
+	}
+	if got := matrix.Count("3.1.0-1", jammyArm); got != 0 {
+		t.Errorf("expected no packages for 3.1.0-1 on %s, got %d", jammyArm, got)
+	}
+
+	var required []Platform
+	for _, s := range []string{"ubuntu/jammy:arm64", "el/9", ":amd64", "ubuntu/noble:amd64"} {
+		platform, err := ParsePlatform(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		required = append(required, platform)
+	}
+
+	noble := Platform{Distro: "ubuntu/noble", Arch: "amd64"}
+	if got := matrix.Missing("3.2.0-1", required); !reflect.DeepEqual(got, []Platform{noble}) {
+		t.Errorf("expected 3.2.0-1 to only be missing %s, got %v", noble, got)
+	}
+	if got := matrix.Missing("3.1.0-1", required); len(got) != 3 {
+		t.Errorf("expected 3.1.0-1 to be missing 3 platforms, got %v", got)
+	}
+
+	matrix.Require(required)
+	if len(matrix.Platforms) != 4 || matrix.Platforms[3] != noble {
+		t.Errorf("expected %s to be added to the platforms, got %v", noble, matrix.Platforms)
+	}
+}
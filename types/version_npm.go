@@ -0,0 +1,115 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type npmScheme struct{}
+
+// NpmScheme compares node package versions the way the npm semver package
+// does. Unlike SemverScheme, versions must have exactly three numeric
+// components (i.e. 1.2 is rejected).
+var NpmScheme VersionScheme = npmScheme{}
+
+var npmPattern = regexp.MustCompile(`^[v=\s]*` +
+	`(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+	`(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?` +
+	`(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+type npmVersion struct {
+	major, minor, patch string
+	prerelease          []string
+}
+
+func parseNpmVersion(version string) (npmVersion, error) {
+	match := npmPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return npmVersion{}, fmt.Errorf("error parsing version %s: not a valid npm semver version", version)
+	}
+
+	v := npmVersion{
+		major: match[1],
+		minor: match[2],
+		patch: match[3],
+	}
+	if match[4] != "" {
+		v.prerelease = strings.Split(match[4], ".")
+	}
+
+	return v, nil
+}
+
+func (npmScheme) Name() string {
+	return "npm"
+}
+
+func (npmScheme) Compare(a, b string) (int, error) {
+	versionA, err := parseNpmVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	versionB, err := parseNpmVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return versionA.compare(versionB), nil
+}
+
+func (npmScheme) IsPrerelease(version string) bool {
+	v, err := parseNpmVersion(version)
+	if err != nil {
+		return false
+	}
+	return len(v.prerelease) > 0
+}
+
+func (v npmVersion) compare(other npmVersion) int {
+	if c := compareNumeric(v.major, other.major); c != 0 {
+		return c
+	}
+	if c := compareNumeric(v.minor, other.minor); c != 0 {
+		return c
+	}
+	if c := compareNumeric(v.patch, other.patch); c != 0 {
+		return c
+	}
+
+	// A version without a pre-release sorts after one with a pre-release.
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	// Numeric identifiers sort before alphanumeric ones.
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		a := v.prerelease[i]
+		b := other.prerelease[i]
+		numA := isDigits(a)
+		numB := isDigits(b)
+
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(a, b)
+		case numA:
+			c = -1
+		case numB:
+			c = 1
+		default:
+			c = sign(strings.Compare(a, b))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return sign(len(v.prerelease) - len(other.prerelease))
+}
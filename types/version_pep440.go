@@ -0,0 +1,220 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type pep440Scheme struct{}
+
+// Pep440Scheme compares Python package versions as specified by PEP 440
+// (i.e. 1.0.dev1 < 1.0a1 < 1.0rc1 < 1.0 < 1.0.post1).
+var Pep440Scheme VersionScheme = pep440Scheme{}
+
+// pep440Pattern is the version pattern from the PEP 440 appendix, which
+// also accepts the alternative spellings that normalize to canonical
+// versions.
+var pep440Pattern = regexp.MustCompile(`(?i)^v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?:[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?:-(?P<post_n1>[0-9]+)|[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?)?` +
+	`(?:[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+var pep440PreReleaseLabels = map[string]int{
+	"a":       0,
+	"alpha":   0,
+	"b":       1,
+	"beta":    1,
+	"c":       2,
+	"rc":      2,
+	"pre":     2,
+	"preview": 2,
+}
+
+type pep440Version struct {
+	epoch   string
+	release []string
+
+	hasPre   bool
+	preLabel int
+	preNum   string
+
+	hasPost bool
+	postNum string
+
+	hasDev bool
+	devNum string
+
+	local []string
+}
+
+func parsePep440Version(version string) (pep440Version, error) {
+	match := pep440Pattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return pep440Version{}, fmt.Errorf("error parsing version %s: not a valid PEP 440 version", version)
+	}
+
+	group := func(name string) string {
+		return match[pep440Pattern.SubexpIndex(name)]
+	}
+
+	v := pep440Version{
+		epoch:   group("epoch"),
+		release: strings.Split(group("release"), "."),
+	}
+
+	if label := group("pre_l"); label != "" {
+		v.hasPre = true
+		v.preLabel = pep440PreReleaseLabels[strings.ToLower(label)]
+		v.preNum = group("pre_n")
+	}
+
+	if n := group("post_n1"); n != "" {
+		v.hasPost = true
+		v.postNum = n
+	} else if group("post_l") != "" {
+		v.hasPost = true
+		v.postNum = group("post_n2")
+	}
+
+	if group("dev_l") != "" {
+		v.hasDev = true
+		v.devNum = group("dev_n")
+	}
+
+	if local := group("local"); local != "" {
+		v.local = strings.FieldsFunc(strings.ToLower(local), func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+	}
+
+	return v, nil
+}
+
+func (pep440Scheme) Name() string {
+	return "pep440"
+}
+
+func (pep440Scheme) Compare(a, b string) (int, error) {
+	versionA, err := parsePep440Version(a)
+	if err != nil {
+		return 0, err
+	}
+
+	versionB, err := parsePep440Version(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return versionA.compare(versionB), nil
+}
+
+// IsPrerelease returns whether or not the version is a pre-release or a
+// development release.
+func (pep440Scheme) IsPrerelease(version string) bool {
+	v, err := parsePep440Version(version)
+	if err != nil {
+		return false
+	}
+	return v.hasPre || v.hasDev
+}
+
+// preRank orders versions without a pre-release segment relative to those
+// with one. A bare development release (1.0.dev1) sorts before every
+// pre-release of the same version, while a final release sorts after them.
+func (v pep440Version) preRank() int {
+	switch {
+	case v.hasPre:
+		return 0
+	case !v.hasPost && v.hasDev:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func (v pep440Version) compare(other pep440Version) int {
+	if c := compareNumeric(v.epoch, other.epoch); c != 0 {
+		return c
+	}
+
+	// Trailing zeros are insignificant, so 1.0 == 1.0.0. Missing release
+	// segments compare as zero.
+	for i := 0; i < len(v.release) || i < len(other.release); i++ {
+		var a, b string
+		if i < len(v.release) {
+			a = v.release[i]
+		}
+		if i < len(other.release) {
+			b = other.release[i]
+		}
+		if c := compareNumeric(a, b); c != 0 {
+			return c
+		}
+	}
+
+	if c := sign(v.preRank() - other.preRank()); c != 0 {
+		return c
+	}
+	if v.hasPre {
+		if c := sign(v.preLabel - other.preLabel); c != 0 {
+			return c
+		}
+		if c := compareNumeric(v.preNum, other.preNum); c != 0 {
+			return c
+		}
+	}
+
+	// Versions without a post-release segment sort before those with one.
+	if v.hasPost != other.hasPost {
+		if v.hasPost {
+			return 1
+		}
+		return -1
+	}
+	if c := compareNumeric(v.postNum, other.postNum); c != 0 {
+		return c
+	}
+
+	// Versions without a development segment sort after those with one.
+	if v.hasDev != other.hasDev {
+		if v.hasDev {
+			return -1
+		}
+		return 1
+	}
+	if c := compareNumeric(v.devNum, other.devNum); c != 0 {
+		return c
+	}
+
+	return comparePep440Local(v.local, other.local)
+}
+
+// comparePep440Local compares local version labels. Numeric segments sort
+// after alphanumeric ones and a version with a local label sorts after the
+// same version without one.
+func comparePep440Local(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		numA := isDigits(a[i])
+		numB := isDigits(b[i])
+
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(a[i], b[i])
+		case numA:
+			c = 1
+		case numB:
+			c = -1
+		default:
+			c = sign(strings.Compare(a[i], b[i]))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return sign(len(a) - len(b))
+}
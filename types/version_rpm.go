@@ -0,0 +1,180 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+type rpmScheme struct{}
+
+// RpmScheme compares versions the way rpm does: [epoch:]version[-release]
+// where "~" sorts before the end of the version and "^" sorts after it.
+var RpmScheme VersionScheme = rpmScheme{}
+
+func (rpmScheme) Name() string {
+	return "rpm"
+}
+
+func (rpmScheme) Compare(a, b string) (int, error) {
+	epochA, versionA, releaseA, err := parseRpmVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	epochB, versionB, releaseB, err := parseRpmVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if c := compareNumeric(epochA, epochB); c != 0 {
+		return c, nil
+	}
+	if c := rpmVerCmp(versionA, versionB); c != 0 {
+		return c, nil
+	}
+
+	// Like rpm, only compare releases when both versions have one.
+	if releaseA == "" || releaseB == "" {
+		return 0, nil
+	}
+	return rpmVerCmp(releaseA, releaseB), nil
+}
+
+// IsPrerelease returns whether or not the version (ignoring the release)
+// contains "~", which sorts the version before the final release.
+func (rpmScheme) IsPrerelease(version string) bool {
+	_, v, _, err := parseRpmVersion(version)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(v, "~")
+}
+
+// parseRpmVersion splits an rpm EVR string into its epoch, version and
+// release.
+func parseRpmVersion(evr string) (epoch, version, release string, err error) {
+	version = strings.TrimSpace(evr)
+
+	if i := strings.Index(version, ":"); i != -1 {
+		epoch = version[:i]
+		version = version[i+1:]
+		if epoch == "" || !isDigits(epoch) {
+			return "", "", "", fmt.Errorf("error parsing version %s: epoch is not a number", evr)
+		}
+	}
+
+	if i := strings.LastIndex(version, "-"); i != -1 {
+		release = version[i+1:]
+		version = version[:i]
+	}
+
+	if version == "" {
+		return "", "", "", fmt.Errorf("error parsing version %s: version is empty", evr)
+	}
+
+	return epoch, version, release, nil
+}
+
+func isRpmSeparator(c byte) bool {
+	return !isDigit(c) && !isAlpha(c) && c != '~' && c != '^'
+}
+
+// rpmVerCmp is a port of rpmvercmp from rpm.
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && isRpmSeparator(a[i]) {
+			i++
+		}
+		for j < len(b) && isRpmSeparator(b[j]) {
+			j++
+		}
+
+		// "~" sorts before everything, including the end of the version.
+		if (i < len(a) && a[i] == '~') || (j < len(b) && b[j] == '~') {
+			if i >= len(a) || a[i] != '~' {
+				return 1
+			}
+			if j >= len(b) || b[j] != '~' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		// "^" sorts after the end of the version but before everything
+		// else.
+		if (i < len(a) && a[i] == '^') || (j < len(b) && b[j] == '^') {
+			if i >= len(a) {
+				return -1
+			}
+			if j >= len(b) {
+				return 1
+			}
+			if a[i] != '^' {
+				return 1
+			}
+			if b[j] != '^' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+
+		// Compare the next segment, which is either all digits or all
+		// letters depending on the first character of a.
+		isNum := isDigit(a[i])
+		matches := isAlpha
+		if isNum {
+			matches = isDigit
+		}
+
+		startA := i
+		for i < len(a) && matches(a[i]) {
+			i++
+		}
+		startB := j
+		for j < len(b) && matches(b[j]) {
+			j++
+		}
+
+		segA := a[startA:i]
+		segB := b[startB:j]
+
+		// Segments of different types: numbers are newer than letters.
+		if segB == "" {
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+
+		var c int
+		if isNum {
+			c = compareNumeric(segA, segB)
+		} else {
+			c = sign(strings.Compare(segA, segB))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	if i >= len(a) && j >= len(b) {
+		return 0
+	}
+	if i < len(a) {
+		return 1
+	}
+	return -1
+}
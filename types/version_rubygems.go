@@ -0,0 +1,113 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type rubygemsScheme struct{}
+
+// RubygemsScheme compares gem versions the way Gem::Version does. Any
+// version containing a letter is a pre-release (i.e. 1.0.0.pre < 1.0.0).
+var RubygemsScheme VersionScheme = rubygemsScheme{}
+
+var (
+	rubygemsPattern = regexp.MustCompile(`^[0-9]+(?:\.[0-9a-zA-Z]+)*(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+	rubygemsSegmentPattern = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+)
+
+func (rubygemsScheme) Name() string {
+	return "rubygems"
+}
+
+func (rubygemsScheme) Compare(a, b string) (int, error) {
+	segmentsA, err := rubygemsSegments(a)
+	if err != nil {
+		return 0, err
+	}
+
+	segmentsB, err := rubygemsSegments(b)
+	if err != nil {
+		return 0, err
+	}
+
+	// Missing segments compare as zero. Letters sort before numbers.
+	for i := 0; i < len(segmentsA) || i < len(segmentsB); i++ {
+		segA, segB := "0", "0"
+		if i < len(segmentsA) {
+			segA = segmentsA[i]
+		}
+		if i < len(segmentsB) {
+			segB = segmentsB[i]
+		}
+
+		numA := isDigits(segA)
+		numB := isDigits(segB)
+
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(segA, segB)
+		case numA:
+			c = 1
+		case numB:
+			c = -1
+		default:
+			c = sign(strings.Compare(segA, segB))
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// IsPrerelease returns whether or not the version contains a letter.
+func (rubygemsScheme) IsPrerelease(version string) bool {
+	segments, err := rubygemsSegments(version)
+	if err != nil {
+		return false
+	}
+	for _, segment := range segments {
+		if !isDigits(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// rubygemsSegments returns the canonical segments of a gem version: the
+// release and pre-release segments, each with trailing zeros removed.
+func rubygemsSegments(version string) ([]string, error) {
+	version = strings.TrimSpace(version)
+	if !rubygemsPattern.MatchString(version) {
+		return nil, fmt.Errorf("error parsing version %s: not a valid gem version", version)
+	}
+	version = strings.ReplaceAll(version, "-", ".pre.")
+
+	segments := rubygemsSegmentPattern.FindAllString(version, -1)
+
+	prerelease := len(segments)
+	for i, segment := range segments {
+		if !isDigits(segment) {
+			prerelease = i
+			break
+		}
+	}
+
+	release := trimZeroSegments(segments[:prerelease])
+	pre := trimZeroSegments(segments[prerelease:])
+
+	return append(release, pre...), nil
+}
+
+func trimZeroSegments(segments []string) []string {
+	end := len(segments)
+	for end > 0 && isDigits(segments[end-1]) && compareNumeric(segments[end-1], "0") == 0 {
+		end--
+	}
+	return append([]string{}, segments[:end]...)
+}
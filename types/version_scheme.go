@@ -0,0 +1,228 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+)
+
+// VersionScheme compares package versions using the rules of a particular
+// packaging ecosystem.
+type VersionScheme interface {
+	// Name is the name of the version scheme (i.e. semver, dpkg, rpm).
+	Name() string
+
+	// Compare returns -1 if a is older than b, 1 if a is newer than b and 0
+	// if they are equal. An error is returned if either version is not
+	// valid in the scheme.
+	Compare(a, b string) (int, error)
+
+	// IsPrerelease returns whether or not version is a pre-release (i.e.
+	// 1.0.0-rc.1, 1.0~rc1, 1.0rc1). Invalid versions are never
+	// pre-releases.
+	IsPrerelease(version string) bool
+}
+
+var versionSchemes = map[string]VersionScheme{
+	SemverScheme.Name():   SemverScheme,
+	DpkgScheme.Name():     DpkgScheme,
+	RpmScheme.Name():      RpmScheme,
+	Pep440Scheme.Name():   Pep440Scheme,
+	RubygemsScheme.Name(): RubygemsScheme,
+	NpmScheme.Name():      NpmScheme,
+}
+
+// VersionSchemeNames returns the names of every supported version scheme.
+func VersionSchemeNames() []string {
+	names := make([]string, 0, len(versionSchemes))
+	for name := range versionSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetVersionScheme returns the version scheme with the given name.
+func GetVersionScheme(name string) (VersionScheme, error) {
+	scheme, ok := versionSchemes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown version scheme %q (must be one of: %s)", name, strings.Join(VersionSchemeNames(), ", "))
+	}
+	return scheme, nil
+}
+
+// VersionSchemeForPackageType returns the version scheme used by packages of
+// the given type (i.e. deb, rpm, python). Package types without a native
+// scheme use semantic versioning.
+func VersionSchemeForPackageType(packageType string) VersionScheme {
+	switch packageType {
+	case "deb", "dsc":
+		return DpkgScheme
+	case "rpm":
+		return RpmScheme
+	case "python":
+		return Pep440Scheme
+	case "gem":
+		return RubygemsScheme
+	case "node":
+		return NpmScheme
+	default:
+		return SemverScheme
+	}
+}
+
+// SortVersions sorts versions in descending order (newest first) using the
+// given scheme. In every scheme, versions that are equal in the scheme are
+// ordered with the natural ordering of SortVersionsFallback, so that the
+// result doesn't depend on the input order; for example, the package release
+// of 1.2.3+r1 sorts it before 1.2.3+r0 in semver, which ignores build
+// metadata. An *InvalidVersionsError listing every version that is not valid
+// in the scheme is returned if there are any.
+func SortVersions(scheme VersionScheme, versions []string) error {
+	if invalid := InvalidVersions(scheme, versions); len(invalid) > 0 {
+		return &InvalidVersionsError{
+			Scheme:   scheme.Name(),
+			Versions: invalid,
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		if c, _ := scheme.Compare(versions[i], versions[j]); c != 0 {
+			return c > 0
+		}
+		return dpkgVerRevCmp(versions[i], versions[j]) > 0
+	})
+
+	return nil
+}
+
+// InvalidVersions returns the versions that are not valid in the given
+// scheme.
+func InvalidVersions(scheme VersionScheme, versions []string) []string {
+	var invalid []string
+	for _, version := range versions {
+		if _, err := scheme.Compare(version, version); err != nil {
+			invalid = append(invalid, version)
+		}
+	}
+	return invalid
+}
+
+// SortVersionsFallback sorts versions that are not valid in any particular
+// scheme in descending order using a natural ordering: runs of digits are
+// compared numerically and everything else is compared character by
+// character, the same way dpkg compares upstream versions. For example,
+// 20240102.git1 sorts before 20240101.git3f2a, which sorts before
+// 9.snapshot.
+func SortVersionsFallback(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		if c := dpkgVerRevCmp(versions[i], versions[j]); c != 0 {
+			return c > 0
+		}
+		return versions[i] > versions[j]
+	})
+}
+
+// CompareVersionsLenient compares a and b with scheme like Compare, except
+// that it never fails: versions that are not valid in scheme sort before
+// valid ones and are compared with each other using the natural ordering of
+// SortVersionsFallback.
+func CompareVersionsLenient(scheme VersionScheme, a, b string) int {
+	_, errA := scheme.Compare(a, a)
+	_, errB := scheme.Compare(b, b)
+	switch {
+	case errA != nil && errB != nil:
+		return dpkgVerRevCmp(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	c, _ := scheme.Compare(a, b)
+	return c
+}
+
+type InvalidVersionsError struct {
+	Scheme   string
+	Versions []string
+}
+
+func (e *InvalidVersionsError) Error() string {
+	return fmt.Sprintf("error parsing version: %d version(s) are not valid %s versions: %s",
+		len(e.Versions), e.Scheme, strings.Join(e.Versions, ", "))
+}
+
+type semverScheme struct{}
+
+// SemverScheme compares versions as semantic versions.
+var SemverScheme VersionScheme = semverScheme{}
+
+func (semverScheme) Name() string {
+	return "semver"
+}
+
+func (semverScheme) Compare(a, b string) (int, error) {
+	versionA, err := semver.NewVersion(a)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing version %s: %s", a, err)
+	}
+
+	versionB, err := semver.NewVersion(b)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing version %s: %s", b, err)
+	}
+
+	return versionA.Compare(versionB), nil
+}
+
+func (semverScheme) IsPrerelease(version string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return v.Prerelease() != ""
+}
+
+// sign returns -1, 0 or 1 depending on the sign of n.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareNumeric compares two strings of digits by numeric value without
+// converting them, so arbitrarily long numbers are supported. Empty strings
+// are treated as zero.
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return sign(strings.Compare(a, b))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
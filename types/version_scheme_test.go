@@ -0,0 +1,262 @@
+package types
+
+import (
+	"testing"
+)
+
+type versionComparison struct {
+	a, b string
+	want int
+}
+
+func testVersionScheme(t *testing.T, scheme VersionScheme, tests []versionComparison) {
+	t.Helper()
+	for _, tt := range tests {
+		got, err := scheme.Compare(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("%s: Compare(%q, %q) returned error: %s", scheme.Name(), tt.a, tt.b, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Compare(%q, %q) = %d, want %d", scheme.Name(), tt.a, tt.b, got, tt.want)
+		}
+
+		// Comparisons must be antisymmetric.
+		got, _ = scheme.Compare(tt.b, tt.a)
+		if got != -tt.want {
+			t.Errorf("%s: Compare(%q, %q) = %d, want %d", scheme.Name(), tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestDpkgScheme(t *testing.T) {
+	testVersionScheme(t, DpkgScheme, []versionComparison{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0-0", 0},
+		{"0:1.0", "1.0", 0},
+		{"1.0-1", "1.0-01", 0},
+		{"1.0", "1.1", -1},
+		{"1.9", "1.10", -1},
+		{"2.0.0.201", "2.0.0.3", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0~", "1.0", -1},
+		{"1.0", "1.0a", -1},
+		{"1.0a", "1.0+", -1},
+		{"1.0+dfsg", "1.0", 1},
+		{"1:1.0", "2.0", 1},
+		{"1:2.4.1-3ubuntu1", "1:2.4.1-3", 1},
+		{"1:2.4.1-3ubuntu1", "1:2.4.1-3ubuntu2", -1},
+		{"2.4.1-3ubuntu1", "1:2.4.1-3ubuntu1", -1},
+		{"1.2-3-4", "1.2-3-5", -1},
+		{"10:1", "9:2", 1},
+	})
+}
+
+func TestDpkgSchemeInvalid(t *testing.T) {
+	for _, version := range []string{"", "a1.0", "x:1.0", ":1.0", "1.0-"} {
+		if _, err := DpkgScheme.Compare(version, "1.0"); err == nil {
+			t.Errorf("expected an error for %q", version)
+		}
+	}
+}
+
+func TestRpmScheme(t *testing.T) {
+	testVersionScheme(t, RpmScheme, []versionComparison{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.1", -1},
+		{"1.9", "1.10", -1},
+		{"1.0010", "1.9", 1},
+		{"1.05", "1.5", 0},
+		{"1.0", "1", 1},
+		{"2.0.1a", "2.0.1", 1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"1.0a", "1.0", 1},
+		{"2a", "2.0", -1},
+		{"1.0", "1_0", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.0^git1", "1.0^git2", -1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+		{"1.0-1", "1.0-2", -1},
+		{"1.0-1.el8", "1.0-1.el9", -1},
+		{"1.0-10", "1.0-9", 1},
+		{"1.0", "1.0-5", 0},
+		{"1:1.0-1", "2.0-1", 1},
+		{"0:1.0-1", "1.0-1", 0},
+	})
+}
+
+func TestPep440Scheme(t *testing.T) {
+	testVersionScheme(t, Pep440Scheme, []versionComparison{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1.0", "v1.0", 0},
+		{"1.0.dev1", "1.0a1", -1},
+		{"1.0a1", "1.0a2", -1},
+		{"1.0a2", "1.0b1", -1},
+		{"1.0b1", "1.0rc1", -1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0.0rc1", "1.0.0", -1},
+		{"1.0a1.dev1", "1.0a1", -1},
+		{"1.0", "1.0.post1", -1},
+		{"1.0.post1.dev1", "1.0.post1", -1},
+		{"1.0.post1", "1.0.post2", -1},
+		{"1.0.post2", "1.1.dev1", -1},
+		{"2.0.0.dev3", "2.0.0", -1},
+		{"2.0.0.dev3", "1.9", 1},
+		{"1.0", "1.0+local", -1},
+		{"1.0+abc", "1.0+1", -1},
+		{"1.0+1.2", "1.0+1.10", -1},
+		{"1.0+1", "1.0+1.0", -1},
+		{"1!1.0", "2.0", 1},
+		{"1.0alpha1", "1.0a1", 0},
+		{"1.0-beta.2", "1.0b2", 0},
+		{"1.0c1", "1.0rc1", 0},
+		{"1.0pre1", "1.0rc1", 0},
+		{"1.0-1", "1.0.post1", 0},
+		{"1.0rev1", "1.0.post1", 0},
+		{"1.0.post", "1.0.post0", 0},
+		{"1.0-dev", "1.0.dev0", 0},
+		{"1.0RC1", "1.0rc1", 0},
+	})
+}
+
+func TestPep440SchemeInvalid(t *testing.T) {
+	for _, version := range []string{"", "french toast", "1.0+", "1.0.post1.a1", "1..0"} {
+		if _, err := Pep440Scheme.Compare(version, "1.0"); err == nil {
+			t.Errorf("expected an error for %q", version)
+		}
+	}
+}
+
+func TestRubygemsScheme(t *testing.T) {
+	testVersionScheme(t, RubygemsScheme, []versionComparison{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1", "1.0.0.0", 0},
+		{"1.0", "1.1", -1},
+		{"1.9", "1.10", -1},
+		{"1.0.0.pre", "1.0.0", -1},
+		{"1.0.0.pre", "1.0.0.pre1", -1},
+		{"1.0.0.pre1", "1.0.0.pre2", -1},
+		{"1.0.0.alpha", "1.0.0.beta", -1},
+		{"1.0.0.beta", "1.0.0.rc1", -1},
+		{"1.0.0.rc1", "1.0.0", -1},
+		{"1.0.a", "1.0", -1},
+		{"1.0.a", "0.9", 1},
+		{"1.0.0-1", "1.0.0.pre.1", 0},
+		{"1.0.0-1", "1.0.0", -1},
+		{"1.0.b1", "1.0.a.2", 1},
+		{"1.0a", "1.0.a", 0},
+		{"5.x", "5.0.0.rc2", 1},
+		{"1.0.0.rc.1", "1.0.0.rc.0.1", 1},
+	})
+}
+
+func TestRubygemsSchemeInvalid(t *testing.T) {
+	for _, version := range []string{"", "junk", "1.0\n2.0", "1..2", "1.2 3.4"} {
+		if _, err := RubygemsScheme.Compare(version, "1.0"); err == nil {
+			t.Errorf("expected an error for %q", version)
+		}
+	}
+}
+
+func TestNpmScheme(t *testing.T) {
+	testVersionScheme(t, NpmScheme, []versionComparison{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.0.0", "=1.0.0", 0},
+		{"1.0.0", "1.0.0+build.1", 0},
+		{"1.0.0", "1.0.1", -1},
+		{"1.9.0", "1.10.0", -1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta", "1.0.0-beta.2", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"2.0.0-0", "1.9.9", 1},
+		{"1.0.0-1", "1.0.0-a", -1},
+	})
+}
+
+func TestNpmSchemeInvalid(t *testing.T) {
+	for _, version := range []string{"", "1", "1.0", "1.0.0.0", "01.0.0", "1.0.0-", "1.0.0-a..b"} {
+		if _, err := NpmScheme.Compare(version, "1.0.0"); err == nil {
+			t.Errorf("expected an error for %q", version)
+		}
+	}
+}
+
+func TestVersionSchemeForPackageType(t *testing.T) {
+	tests := map[string]VersionScheme{
+		"deb":    DpkgScheme,
+		"dsc":    DpkgScheme,
+		"rpm":    RpmScheme,
+		"python": Pep440Scheme,
+		"gem":    RubygemsScheme,
+		"node":   NpmScheme,
+		"java":   SemverScheme,
+		"":       SemverScheme,
+	}
+	for packageType, want := range tests {
+		if got := VersionSchemeForPackageType(packageType); got != want {
+			t.Errorf("VersionSchemeForPackageType(%q) = %s, want %s", packageType, got.Name(), want.Name())
+		}
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	tests := []struct {
+		scheme  VersionScheme
+		version string
+		want    bool
+	}{
+		{SemverScheme, "1.0.0-rc.1", true},
+		{SemverScheme, "1.0.0+build", false},
+		{DpkgScheme, "1.0~rc1-1", true},
+		{DpkgScheme, "1.0-1~bpo1", false},
+		{RpmScheme, "1.0~rc1-1.el9", true},
+		{RpmScheme, "1.0^git1-1", false},
+		{Pep440Scheme, "1.0rc1", true},
+		{Pep440Scheme, "1.0.dev1", true},
+		{Pep440Scheme, "1.0.post1", false},
+		{RubygemsScheme, "1.0.0.pre", true},
+		{RubygemsScheme, "1.0.0", false},
+		{NpmScheme, "1.0.0-beta.2", true},
+		{NpmScheme, "1.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := tt.scheme.IsPrerelease(tt.version); got != tt.want {
+			t.Errorf("%s: IsPrerelease(%q) = %t, want %t", tt.scheme.Name(), tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestCompareVersionsLenient(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.2.0", 1},
+		{"1.2.0", "1.2.0", 0},
+		{"20240101.git3f2a", "1.2.0", -1},
+		{"1.2.0", "20240101.git3f2a", 1},
+		{"20240102.git1", "20240101.git3f2a", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersionsLenient(NpmScheme, tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersionsLenient(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}